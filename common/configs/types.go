@@ -12,9 +12,10 @@ const (
 )
 
 type RuntimeConfig struct {
-	App appCfg `mapstructure:"App"`
-	API apiCfg `mapstructure:"API"`
-	DB  dbCfg  `mapstructure:"DB"`
+	App  appCfg  `mapstructure:"App"`
+	API  apiCfg  `mapstructure:"API"`
+	DB   dbCfg   `mapstructure:"DB"`
+	Mail mailCfg `mapstructure:"Mail"`
 }
 
 type appCfg struct {
@@ -27,16 +28,68 @@ type appCfg struct {
 }
 
 type apiCfg struct {
-	BaseURL    string `mapstructure:"BaseURL"`
-	Timeout    int    `mapstructure:"Timeout"`
-	DebugMode  bool   `mapstructure:"DebugMode"`
-	BCryptSalt int    `mapstructure:"BCRYPT_SALT"`
-	JWT        jwt    `mapstructure:"JWT"`
+	BaseURL             string        `mapstructure:"BaseURL"`
+	Timeout             int           `mapstructure:"Timeout"`
+	DebugMode           bool          `mapstructure:"DebugMode"`
+	BCryptSalt          int           `mapstructure:"BCRYPT_SALT"`
+	PasswordResetExpire int           `mapstructure:"PasswordResetExpire"`
+	JWT                 jwt           `mapstructure:"JWT"`
+	LoginThrottle       loginThrottle `mapstructure:"LoginThrottle"`
+	Argon2              argon2        `mapstructure:"Argon2"`
+	OIDC                oidc          `mapstructure:"OIDC"`
+	Policy              policy        `mapstructure:"Policy"`
 }
 
 type jwt struct {
-	Expire    int    `mapstructure:"Expire"`
-	JWTSecret string `mapstructure:"JWT_SECRET"`
+	Expire             int      `mapstructure:"Expire"`
+	RefreshExpire      int      `mapstructure:"RefreshExpire"`
+	VerificationExpire int      `mapstructure:"VerificationExpire"`
+	MFAPendingExpire   int      `mapstructure:"MFAPendingExpire"`
+	RevocationCacheTTL int      `mapstructure:"RevocationCacheTTL"`
+	SigningKeyID       string   `mapstructure:"SigningKeyID"`
+	Keys               []jwtKey `mapstructure:"Keys"`
+	JWTSecret          string   `mapstructure:"JWT_SECRET"`
+}
+
+type jwtKey struct {
+	ID             string `mapstructure:"ID"`
+	Algorithm      string `mapstructure:"Algorithm"`
+	PrivateKeyFile string `mapstructure:"PrivateKeyFile"`
+	PublicKeyFile  string `mapstructure:"PublicKeyFile"`
+}
+
+type loginThrottle struct {
+	MaxAttempts int `mapstructure:"MaxAttempts"`
+	Window      int `mapstructure:"Window"`
+	BaseLockout int `mapstructure:"BaseLockout"`
+	MaxLockout  int `mapstructure:"MaxLockout"`
+}
+
+type argon2 struct {
+	Memory      uint32 `mapstructure:"Memory"`
+	Iterations  uint32 `mapstructure:"Iterations"`
+	Parallelism uint8  `mapstructure:"Parallelism"`
+}
+
+type oidc struct {
+	Issuer       string   `mapstructure:"Issuer"`
+	ClientID     string   `mapstructure:"ClientID"`
+	ClientSecret string   `mapstructure:"ClientSecret"`
+	RedirectURL  string   `mapstructure:"RedirectURL"`
+	Scopes       []string `mapstructure:"Scopes"`
+	StateExpire  int      `mapstructure:"StateExpire"`
+}
+
+type policy struct {
+	PasswordMinLength     int  `mapstructure:"PasswordMinLength"`
+	PasswordMaxLength     int  `mapstructure:"PasswordMaxLength"`
+	RequireUpper          bool `mapstructure:"RequireUpper"`
+	RequireLower          bool `mapstructure:"RequireLower"`
+	RequireDigit          bool `mapstructure:"RequireDigit"`
+	RequireSymbol         bool `mapstructure:"RequireSymbol"`
+	RejectCommonPasswords bool `mapstructure:"RejectCommonPasswords"`
+	NameMinLength         int  `mapstructure:"NameMinLength"`
+	NameMaxLength         int  `mapstructure:"NameMaxLength"`
 }
 
 type dbCfg struct {
@@ -48,3 +101,8 @@ type dbCfg struct {
 	Params      []string `mapstructure:"DB_PARAMS"`
 	MaxConnPool int      `mapstructure:"MaxConnPool"`
 }
+
+type mailCfg struct {
+	From string `mapstructure:"From"`
+	Dir  string `mapstructure:"Dir"`
+}
@@ -0,0 +1,68 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+)
+
+const (
+	mailDirPerm  = 0o750
+	mailFilePerm = 0o600
+)
+
+// FileMailer writes every message as an .eml file into a directory instead of sending it,
+// which is enough for local development and tests.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) *FileMailer {
+	return &FileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (f FileMailer) Send(ctx context.Context, msg Message) error {
+	callerInfo := "[FileMailer.Send]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	if err := os.MkdirAll(f.dir, mailDirPerm); err != nil {
+		l.Error("failed to create mail directory", zap.Error(err))
+		return err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("From: %s\r\n", f.from))
+	sb.WriteString(fmt.Sprintf("To: %s\r\n", msg.To))
+	sb.WriteString(fmt.Sprintf("Subject: %s\r\n", msg.Subject))
+	sb.WriteString(fmt.Sprintf("Date: %s\r\n", time.Now().Format(time.RFC1123Z)))
+	sb.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	sb.WriteString(msg.Body)
+
+	filename := filepath.Join(f.dir, id.New().String()+".eml")
+	if err := os.WriteFile(filename, []byte(sb.String()), mailFilePerm); err != nil {
+		l.Error("failed to write mail file", zap.Error(err))
+		return err
+	}
+
+	l.Info("mail written",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("file", filename),
+	)
+
+	return nil
+}
+
+var _ Mailer = (*FileMailer)(nil)
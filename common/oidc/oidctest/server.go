@@ -0,0 +1,198 @@
+// Package oidctest provides an in-process OpenID Connect identity provider for tests.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Server serves discovery, JWKS and the token endpoint of a fake identity provider.
+// Authorization codes are issued with IssueCode instead of a login page.
+type Server struct {
+	*httptest.Server
+	ClientID string
+
+	mu           sync.Mutex
+	key          *rsa.PrivateKey
+	kid          string
+	keyCount     int
+	codes        map[string]grant
+	jwksRequests int
+}
+
+type grant struct {
+	codeChallenge string
+	claims        jwt.MapClaims
+}
+
+// NewServer starts a fake identity provider that is closed when the test ends.
+func NewServer(t testing.TB, clientID string) *Server {
+	t.Helper()
+
+	s := &Server{
+		ClientID: clientID,
+		codes:    make(map[string]grant),
+	}
+	s.RotateKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/token", s.token)
+
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Issuer returns the issuer identifier of the server.
+func (s *Server) Issuer() string {
+	return s.URL
+}
+
+// RotateKey replaces the signing key, ID tokens issued afterwards carry a new kid.
+func (s *Server) RotateKey(t testing.TB) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate signing key: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keyCount++
+	s.key, s.kid = key, fmt.Sprintf("key-%d", s.keyCount)
+}
+
+// JWKSRequests returns how often the key set was fetched.
+func (s *Server) JWKSRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.jwksRequests
+}
+
+// Claims returns valid ID token claims for the client, tests change them to build invalid tokens.
+func (s *Server) Claims(nonce, subject, email string) jwt.MapClaims {
+	now := time.Now()
+
+	return jwt.MapClaims{
+		"iss":            s.Issuer(),
+		"sub":            subject,
+		"aud":            s.ClientID,
+		"exp":            now.Add(time.Hour).Unix(),
+		"iat":            now.Unix(),
+		"nonce":          nonce,
+		"email":          email,
+		"email_verified": true,
+		"name":           "Test User",
+	}
+}
+
+// IssueCode returns an authorization code redeemable once with the verifier of codeChallenge,
+// the ID token handed out for it carries claims.
+func (s *Server) IssueCode(t testing.TB, codeChallenge string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("generate code: %v", err)
+	}
+	code := base64.RawURLEncoding.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.codes[code] = grant{codeChallenge: codeChallenge, claims: claims}
+
+	return code
+}
+
+func (s *Server) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 s.Issuer(),
+		"authorization_endpoint": s.URL + "/authorize",
+		"token_endpoint":         s.URL + "/token",
+		"jwks_uri":               s.URL + "/jwks",
+	})
+}
+
+func (s *Server) jwks(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jwksRequests++
+	writeJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeError(w, "invalid_request")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := r.PostForm.Get("code")
+	g, ok := s.codes[code]
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("client_id") != s.ClientID {
+		writeError(w, "invalid_grant")
+		return
+	}
+	delete(s.codes, code)
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.codeChallenge {
+		writeError(w, "invalid_grant")
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, g.claims)
+	token.Header["kid"] = s.kid
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func writeError(w http.ResponseWriter, code string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
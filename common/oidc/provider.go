@@ -0,0 +1,362 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+)
+
+const (
+	discoveryPath       = "/.well-known/openid-configuration"
+	defaultHTTPTimeout  = 10 * time.Second
+	maxResponseSize     = 1 << 20
+	randomStringLength  = 32
+	keysRefreshInterval = time.Minute
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid id token")
+	ErrExchangeFailed = errors.New("authorization code exchange failed")
+	ErrDiscovery      = errors.New("openid connect discovery failed")
+)
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims are the ID token claims the login flow relies on.
+type Claims struct {
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified booleanLike `json:"email_verified"`
+	Name          string      `json:"name"`
+	AuthorizedBy  string      `json:"azp"`
+	jwt.RegisteredClaims
+}
+
+// booleanLike accepts both true and "true", some identity providers send the latter.
+type booleanLike bool
+
+func (b *booleanLike) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case bool:
+		*b = booleanLike(v)
+	case string:
+		*b = booleanLike(strings.EqualFold(v, "true"))
+	default:
+		*b = false
+	}
+
+	return nil
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider runs the authorization code flow with PKCE against an OpenID Connect identity provider.
+// The discovery document and signing keys are fetched on first use and cached afterwards.
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *metadata
+	keys          map[string]any
+	keysFetchedAt time.Time
+}
+
+func NewProvider(cfg Config, client *http.Client) *Provider {
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultHTTPTimeout,
+		}
+	}
+
+	return &Provider{
+		cfg:    cfg,
+		client: client,
+	}
+}
+
+// Enabled reports whether an identity provider is configured.
+func (p *Provider) Enabled() bool {
+	return p.cfg.Issuer != "" && p.cfg.ClientID != ""
+}
+
+func (p *Provider) Issuer() string {
+	return p.cfg.Issuer
+}
+
+// AuthCodeURL returns the URL the user is redirected to for signing in at the identity provider.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.cfg.ClientID},
+		"redirect_uri":          {p.cfg.RedirectURL},
+		"scope":                 {strings.Join(p.cfg.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return meta.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the claims of the verified ID token.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error) {
+	callerInfo := "[oidc.Provider.Exchange]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	meta, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Claims{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var token tokenResponse
+	status, err := p.doJSON(req, &token)
+	if err != nil {
+		l.Error("failed to call token endpoint", zap.Error(err))
+		return Claims{}, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+	if status != http.StatusOK || token.IDToken == "" {
+		err = fmt.Errorf("%w: status %d %s %s", ErrExchangeFailed, status, token.Error, token.ErrorDescription)
+		l.Error("token endpoint rejected the code", zap.Error(err))
+		return Claims{}, err
+	}
+
+	claims := Claims{}
+	_, err = jwt.ParseWithClaims(
+		token.IDToken,
+		&claims,
+		p.keyfunc(ctx),
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithIssuer(meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		l.Error("failed to verify id token", zap.Error(err))
+		return Claims{}, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return Claims{}, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	if len(claims.Audience) > 1 && claims.AuthorizedBy != p.cfg.ClientID {
+		return Claims{}, fmt.Errorf("%w: unexpected authorized party", ErrInvalidIDToken)
+	}
+
+	if claims.Subject == "" {
+		return Claims{}, fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return *p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	var meta metadata
+	status, err := p.doJSON(req, &meta)
+	if err != nil {
+		return metadata{}, fmt.Errorf("%w: %v", ErrDiscovery, err)
+	}
+	if status != http.StatusOK {
+		return metadata{}, fmt.Errorf("%w: status %d", ErrDiscovery, status)
+	}
+
+	// the issuer has to match exactly, otherwise ID tokens of another issuer could be accepted
+	if meta.Issuer != p.cfg.Issuer {
+		return metadata{}, fmt.Errorf("%w: issuer %q does not match %q", ErrDiscovery, meta.Issuer, p.cfg.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return metadata{}, fmt.Errorf("%w: incomplete provider metadata", ErrDiscovery)
+	}
+
+	p.metadata = &meta
+	return meta, nil
+}
+
+// keyfunc resolves the signing key of an ID token, the key set is fetched again
+// when the kid is unknown so keys rotated by the identity provider are picked up.
+func (p *Provider) keyfunc(ctx context.Context) jwt.Keyfunc {
+	return func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if key, ok := p.lookupKey(kid); ok {
+			return key, nil
+		}
+
+		if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+
+		keys, err := p.fetchKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.keys, p.keysFetchedAt = keys, time.Now()
+
+		if key, ok := p.lookupKey(kid); ok {
+			return key, nil
+		}
+
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+}
+
+func (p *Provider) lookupKey(kid string) (any, bool) {
+	if key, ok := p.keys[kid]; ok {
+		return key, true
+	}
+
+	// a token without kid is only accepted when there is no doubt about the key
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	return nil, false
+}
+
+func (p *Provider) fetchKeys(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.metadata.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set jwkSet
+	status, err := p.doJSON(req, &set)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch signing keys: status %d", status)
+	}
+
+	return set.publicKeys(), nil
+}
+
+func (p *Provider) doJSON(req *http.Request, v any) (int, error) {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return res.StatusCode, err
+	}
+
+	if err = json.Unmarshal(body, v); err != nil && res.StatusCode == http.StatusOK {
+		return res.StatusCode, err
+	}
+
+	return res.StatusCode, nil
+}
+
+// NewCodeVerifier returns a random PKCE code verifier as described in RFC 7636.
+func NewCodeVerifier() (string, error) {
+	return randomString()
+}
+
+// CodeChallenge returns the S256 challenge of a PKCE code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// NewNonce returns a random nonce binding the ID token to the login it was requested for.
+func NewNonce() (string, error) {
+	return randomString()
+}
+
+func randomString() (string, error) {
+	b := make([]byte, randomStringLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
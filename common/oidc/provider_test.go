@@ -0,0 +1,157 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"cats-social/common/oidc/oidctest"
+)
+
+const testClientID = "cats-social"
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Server) {
+	t.Helper()
+
+	idp := oidctest.NewServer(t, testClientID)
+	provider := NewProvider(Config{
+		Issuer:      idp.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/v1/user/oidc/callback",
+		Scopes:      []string{"openid", "email"},
+	}, idp.Client())
+
+	return provider, idp
+}
+
+func TestExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   func(claims jwt.MapClaims)
+		verifier func(verifier string) string
+		nonce    func(nonce string) string
+		wantErr  error
+	}{
+		{
+			name: "valid id token",
+		},
+		{
+			name:    "nonce mismatch",
+			nonce:   func(string) string { return "another-nonce" },
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name:    "wrong issuer",
+			claims:  func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name:    "wrong audience",
+			claims:  func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name: "expired id token",
+			claims: func(claims jwt.MapClaims) {
+				claims["iat"] = time.Now().Add(-2 * time.Hour).Unix()
+				claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			},
+			wantErr: ErrInvalidIDToken,
+		},
+		{
+			name:     "pkce verifier mismatch",
+			verifier: func(string) string { return "another-verifier" },
+			wantErr:  ErrExchangeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, idp := newTestProvider(t)
+			ctx := context.Background()
+
+			verifier, err := NewCodeVerifier()
+			if err != nil {
+				t.Fatalf("NewCodeVerifier: %v", err)
+			}
+			nonce, err := NewNonce()
+			if err != nil {
+				t.Fatalf("NewNonce: %v", err)
+			}
+
+			claims := idp.Claims(nonce, "subject-1", "user@example.com")
+			if tt.claims != nil {
+				tt.claims(claims)
+			}
+			code := idp.IssueCode(t, CodeChallenge(verifier), claims)
+
+			if tt.verifier != nil {
+				verifier = tt.verifier(verifier)
+			}
+			if tt.nonce != nil {
+				nonce = tt.nonce(nonce)
+			}
+
+			got, err := provider.Exchange(ctx, code, verifier, nonce)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Exchange error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Exchange: %v", err)
+			}
+			if got.Subject != "subject-1" || got.Email != "user@example.com" || !got.EmailVerified {
+				t.Fatalf("Exchange claims = %+v", got)
+			}
+		})
+	}
+}
+
+func TestExchangeRefetchesKeysForUnknownKid(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	ctx := context.Background()
+
+	exchange := func() error {
+		verifier, _ := NewCodeVerifier()
+		nonce, _ := NewNonce()
+		code := idp.IssueCode(t, CodeChallenge(verifier), idp.Claims(nonce, "subject-1", "user@example.com"))
+
+		_, err := provider.Exchange(ctx, code, verifier, nonce)
+		return err
+	}
+
+	if err := exchange(); err != nil {
+		t.Fatalf("first Exchange: %v", err)
+	}
+	if got := idp.JWKSRequests(); got != 1 {
+		t.Fatalf("JWKS requests after first exchange = %d, want 1", got)
+	}
+
+	// a known kid is served from the cache
+	if err := exchange(); err != nil {
+		t.Fatalf("second Exchange: %v", err)
+	}
+	if got := idp.JWKSRequests(); got != 1 {
+		t.Fatalf("JWKS requests with a cached kid = %d, want 1", got)
+	}
+
+	idp.RotateKey(t)
+
+	// refetches are rate limited, pretend the last one is old enough
+	provider.mu.Lock()
+	provider.keysFetchedAt = time.Now().Add(-keysRefreshInterval)
+	provider.mu.Unlock()
+
+	if err := exchange(); err != nil {
+		t.Fatalf("Exchange after key rotation: %v", err)
+	}
+	if got := idp.JWKSRequests(); got != 2 {
+		t.Fatalf("JWKS requests after key rotation = %d, want 2", got)
+	}
+}
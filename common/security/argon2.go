@@ -0,0 +1,153 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+
+	// defaults follow the second recommended option of RFC 9106
+	defaultArgon2idMemory      = 64 * 1024
+	defaultArgon2idIterations  = 3
+	defaultArgon2idParallelism = 4
+)
+
+// Argon2idHasher stores hashes in the PHC string format:
+// $argon2id$v=19$m=<memory KiB>,t=<iterations>,p=<parallelism>$<salt>$<key>
+type Argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+type argon2idHash struct {
+	version     int
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// NewArgon2idHasher creates a hasher with the given parameters, zero values fall back to the defaults.
+func NewArgon2idHasher(memory, iterations uint32, parallelism uint8) *Argon2idHasher {
+	if memory == 0 {
+		memory = defaultArgon2idMemory
+	}
+	if iterations == 0 {
+		iterations = defaultArgon2idIterations
+	}
+	if parallelism == 0 {
+		parallelism = defaultArgon2idParallelism
+	}
+
+	return &Argon2idHasher{
+		memory:      memory,
+		iterations:  iterations,
+		parallelism: parallelism,
+	}
+}
+
+func (a Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.iterations, a.memory, a.parallelism, argon2idKeyLength)
+
+	encoded := fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		a.memory,
+		a.iterations,
+		a.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+
+	return encoded, nil
+}
+
+func (a Argon2idHasher) Compare(hashedPassword, password string) error {
+	decoded, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	key := argon2.IDKey(
+		[]byte(password),
+		decoded.salt,
+		decoded.iterations,
+		decoded.memory,
+		decoded.parallelism,
+		uint32(len(decoded.key)),
+	)
+	if subtle.ConstantTimeCompare(key, decoded.key) != 1 {
+		return ErrPasswordMismatch
+	}
+
+	return nil
+}
+
+func (a Argon2idHasher) Recognizes(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, argon2idPrefix)
+}
+
+func (a Argon2idHasher) NeedsRehash(hashedPassword string) bool {
+	decoded, err := decodeArgon2idHash(hashedPassword)
+	if err != nil {
+		return true
+	}
+
+	return decoded.version != argon2.Version ||
+		decoded.memory != a.memory ||
+		decoded.iterations != a.iterations ||
+		decoded.parallelism != a.parallelism ||
+		len(decoded.salt) != argon2idSaltLength ||
+		len(decoded.key) != argon2idKeyLength
+}
+
+func decodeArgon2idHash(hashedPassword string) (argon2idHash, error) {
+	var decoded argon2idHash
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return decoded, ErrInvalidHashEncoded
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &decoded.version); err != nil {
+		return decoded, ErrInvalidHashEncoded
+	}
+
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &decoded.memory, &decoded.iterations, &decoded.parallelism)
+	if err != nil {
+		return decoded, ErrInvalidHashEncoded
+	}
+
+	decoded.salt, err = base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return decoded, ErrInvalidHashEncoded
+	}
+
+	decoded.key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(decoded.key) == 0 {
+		return decoded, ErrInvalidHashEncoded
+	}
+
+	return decoded, nil
+}
+
+var _ PasswordHasher = (*Argon2idHasher)(nil)
@@ -8,11 +8,25 @@ import (
 	"go.uber.org/zap"
 
 	"cats-social/common/configs"
+	"cats-social/common/id"
 	"cats-social/internal/domain"
 )
 
+const (
+	verificationAudience = "email-verification"
+	mfaPendingAudience   = "mfa-pending"
+)
+
 type AccessTokenClaims struct {
-	User user `json:"user"`
+	User      user      `json:"user"`
+	Verified  bool      `json:"verified"`
+	Roles     []string  `json:"roles,omitempty"`
+	SessionID ulid.ULID `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+type verificationClaims struct {
+	Email string `json:"email"`
 	jwt.RegisteredClaims
 }
 
@@ -22,28 +36,121 @@ type user struct {
 	Name  string    `json:"name"`
 }
 
-func GenerateAccessToken(u domain.User) (string, error) {
+// GenerateAccessToken signs an access token of the session the user logged in with.
+func GenerateAccessToken(u domain.User, sessionID ulid.ULID) (string, error) {
 	callerInfo := "[security.GenerateAccessToken]"
 	l := zap.L().With(zap.String("caller", callerInfo))
 
 	currentTime := time.Now()
 	tokenExp := currentTime.Add(time.Duration(configs.Runtime.API.JWT.Expire) * time.Second)
 
+	roles := make([]string, len(u.Roles))
+	for i, role := range u.Roles {
+		roles[i] = string(role)
+	}
+
 	claims := AccessTokenClaims{
 		User: user{
 			ID:    u.ID,
 			Email: u.Email,
 			Name:  u.Name,
 		},
+		Verified:  u.Verified,
+		Roles:     roles,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.New().String(),
+			IssuedAt:  jwt.NewNumericDate(currentTime),
+			ExpiresAt: jwt.NewNumericDate(tokenExp),
+			NotBefore: jwt.NewNumericDate(currentTime),
+		},
+	}
+
+	signedString, err := signToken(claims)
+	if err != nil {
+		l.Error("Error signing token",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return signedString, nil
+}
+
+// GenerateVerificationToken signs a token proving ownership of the user's current email address.
+// The email is part of the claims, so the token stops working once the address changes.
+func GenerateVerificationToken(u domain.User) (string, error) {
+	callerInfo := "[security.GenerateVerificationToken]"
+	l := zap.L().With(zap.String("caller", callerInfo))
+
+	currentTime := time.Now()
+	tokenExp := currentTime.Add(time.Duration(configs.Runtime.API.JWT.VerificationExpire) * time.Second)
+
+	claims := verificationClaims{
+		Email: u.Email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id.New().String(),
+			Subject:   u.ID.String(),
+			Audience:  jwt.ClaimStrings{verificationAudience},
 			IssuedAt:  jwt.NewNumericDate(currentTime),
 			ExpiresAt: jwt.NewNumericDate(tokenExp),
 			NotBefore: jwt.NewNumericDate(currentTime),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedString, err := token.SignedString([]byte(configs.Runtime.API.JWT.JWTSecret))
+	signedString, err := signToken(claims)
+	if err != nil {
+		l.Error("Error signing token",
+			zap.Error(err),
+		)
+		return "", err
+	}
+
+	return signedString, nil
+}
+
+// ParseVerificationToken validates a token created by GenerateVerificationToken
+// and returns the user ID and email address it was issued for.
+func ParseVerificationToken(tokenString string) (ulid.ULID, string, error) {
+	claims := &verificationClaims{}
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		Keyfunc,
+		jwt.WithAudience(verificationAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return ulid.ULID{}, "", domain.ErrInvalidVerificationToken
+	}
+
+	userID, err := ulid.Parse(claims.Subject)
+	if err != nil {
+		return ulid.ULID{}, "", domain.ErrInvalidVerificationToken
+	}
+
+	return userID, claims.Email, nil
+}
+
+// GenerateMFAPendingToken signs a short-lived token proving the user passed the password check.
+// It has to be exchanged together with a second factor before an access token is issued.
+func GenerateMFAPendingToken(u domain.User) (string, error) {
+	callerInfo := "[security.GenerateMFAPendingToken]"
+	l := zap.L().With(zap.String("caller", callerInfo))
+
+	currentTime := time.Now()
+	tokenExp := currentTime.Add(time.Duration(configs.Runtime.API.JWT.MFAPendingExpire) * time.Second)
+
+	claims := jwt.RegisteredClaims{
+		ID:        id.New().String(),
+		Subject:   u.ID.String(),
+		Audience:  jwt.ClaimStrings{mfaPendingAudience},
+		IssuedAt:  jwt.NewNumericDate(currentTime),
+		ExpiresAt: jwt.NewNumericDate(tokenExp),
+		NotBefore: jwt.NewNumericDate(currentTime),
+	}
+
+	signedString, err := signToken(claims)
 	if err != nil {
 		l.Error("Error signing token",
 			zap.Error(err),
@@ -53,3 +160,26 @@ func GenerateAccessToken(u domain.User) (string, error) {
 
 	return signedString, nil
 }
+
+// ParseMFAPendingToken validates a token created by GenerateMFAPendingToken
+// and returns the user ID it was issued for.
+func ParseMFAPendingToken(tokenString string) (ulid.ULID, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		claims,
+		Keyfunc,
+		jwt.WithAudience(mfaPendingAudience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return ulid.ULID{}, domain.ErrInvalidMFAToken
+	}
+
+	userID, err := ulid.Parse(claims.Subject)
+	if err != nil {
+		return ulid.ULID{}, domain.ErrInvalidMFAToken
+	}
+
+	return userID, nil
+}
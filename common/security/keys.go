@@ -0,0 +1,246 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"cats-social/common/configs"
+)
+
+const (
+	algRS256 = "RS256"
+	algEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnknownKeyID        = errors.New("unknown signing key id")
+	ErrUnexpectedAlgorithm = errors.New("unexpected signing algorithm")
+)
+
+// keys holds the key set loaded by LoadKeys, the HS256 secret is used until then.
+var keys *keySet
+
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	privateKey any
+	publicKey  any
+}
+
+type keySet struct {
+	signing      signingKey
+	verification map[string]signingKey
+}
+
+// JWK is the public part of a verification key as described in RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoadKeys reads the RS256 and EdDSA keys configured under API.JWT.Keys.
+// Tokens are signed with SigningKeyID and verified with any configured key, so a
+// retired key can stay listed with only its public key until its tokens expire.
+// Without configured keys, tokens are signed and verified with the HS256 JWT_SECRET.
+func LoadKeys() error {
+	callerInfo := "[security.LoadKeys]"
+
+	cfg := configs.Runtime.API.JWT
+	if len(cfg.Keys) == 0 {
+		keys = secretKeySet()
+		return nil
+	}
+
+	set := &keySet{
+		verification: make(map[string]signingKey, len(cfg.Keys)),
+	}
+
+	for _, keyCfg := range cfg.Keys {
+		if keyCfg.ID == "" {
+			return fmt.Errorf("%s key without ID", callerInfo)
+		}
+		if _, ok := set.verification[keyCfg.ID]; ok {
+			return fmt.Errorf("%s duplicate key ID %q", callerInfo, keyCfg.ID)
+		}
+
+		key, err := loadKey(keyCfg.Algorithm, keyCfg.PrivateKeyFile, keyCfg.PublicKeyFile)
+		if err != nil {
+			return fmt.Errorf("%s failed to load key %q: %w", callerInfo, keyCfg.ID, err)
+		}
+		key.id = keyCfg.ID
+
+		set.verification[key.id] = key
+	}
+
+	signing, ok := set.verification[cfg.SigningKeyID]
+	if !ok || signing.privateKey == nil {
+		return fmt.Errorf("%s signing key %q is not configured with a private key", callerInfo, cfg.SigningKeyID)
+	}
+	set.signing = signing
+
+	keys = set
+	return nil
+}
+
+func loadKey(algorithm, privateKeyFile, publicKeyFile string) (signingKey, error) {
+	key := signingKey{}
+
+	switch algorithm {
+	case algRS256:
+		key.method = jwt.SigningMethodRS256
+	case algEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return key, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+
+	if privateKeyFile != "" {
+		keyPEM, err := os.ReadFile(privateKeyFile)
+		if err != nil {
+			return key, err
+		}
+
+		var privateKey crypto.PrivateKey
+		if algorithm == algRS256 {
+			privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(keyPEM)
+		} else {
+			privateKey, err = jwt.ParseEdPrivateKeyFromPEM(keyPEM)
+		}
+		if err != nil {
+			return key, err
+		}
+
+		key.privateKey = privateKey
+		key.publicKey = privateKey.(crypto.Signer).Public()
+	}
+
+	if publicKeyFile != "" {
+		keyPEM, err := os.ReadFile(publicKeyFile)
+		if err != nil {
+			return key, err
+		}
+
+		if algorithm == algRS256 {
+			key.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(keyPEM)
+		} else {
+			key.publicKey, err = jwt.ParseEdPublicKeyFromPEM(keyPEM)
+		}
+		if err != nil {
+			return key, err
+		}
+	}
+
+	if key.publicKey == nil {
+		return key, errors.New("either PrivateKeyFile or PublicKeyFile is required")
+	}
+
+	return key, nil
+}
+
+func secretKeySet() *keySet {
+	secret := []byte(configs.Runtime.API.JWT.JWTSecret)
+	key := signingKey{
+		method:     jwt.SigningMethodHS256,
+		privateKey: secret,
+		publicKey:  secret,
+	}
+
+	return &keySet{
+		signing: key,
+		verification: map[string]signingKey{
+			key.id: key,
+		},
+	}
+}
+
+func currentKeys() *keySet {
+	if keys == nil {
+		return secretKeySet()
+	}
+
+	return keys
+}
+
+// signToken signs the claims with the current signing key and sets its kid header.
+func signToken(claims jwt.Claims) (string, error) {
+	key := currentKeys().signing
+
+	token := jwt.NewWithClaims(key.method, claims)
+	if key.id != "" {
+		token.Header["kid"] = key.id
+	}
+
+	return token.SignedString(key.privateKey)
+}
+
+// Keyfunc resolves the verification key of a token by its kid header. Tokens signed
+// with a different algorithm than the key's are rejected to prevent algorithm confusion.
+func Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := currentKeys().verification[kid]
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, ErrUnexpectedAlgorithm
+	}
+
+	return key.publicKey, nil
+}
+
+// PublicJWKS returns every asymmetric verification key, HS256 secrets are never published.
+func PublicJWKS() JWKSet {
+	set := JWKSet{
+		Keys: []JWK{},
+	}
+
+	for _, key := range currentKeys().verification {
+		jwk := JWK{
+			Kid: key.id,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch publicKey := key.publicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool {
+		return set.Keys[i].Kid < set.Keys[j].Kid
+	})
+
+	return set
+}
@@ -1,29 +1,133 @@
 package security
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"errors"
+	"sync"
 
 	"cats-social/common/configs"
 )
 
-// HashPassword generates a secure hash of a password using bcrypt.
-// Returns the hashed password as a hex-encoded string or an error if hashing fails.
-// example for making salt - https://play.golang.org/p/_Aw6WeWC42I
-func HashPassword(password string) (string, error) {
-	// using recommended cost parameters from - https://godoc.org/golang.org/x/crypto/scrypt
-	bhash, err := bcrypt.GenerateFromPassword([]byte(password), configs.Runtime.API.BCryptSalt)
-	if err != nil {
-		return "", err
+var (
+	ErrPasswordMismatch   = errors.New("password does not match")
+	ErrUnknownHashFormat  = errors.New("unknown password hash format")
+	ErrInvalidHashEncoded = errors.New("invalid encoded password hash")
+)
+
+// PasswordHasher hashes passwords in a self-describing format, so a stored hash
+// tells which algorithm and parameters are needed to verify it.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Compare(hashedPassword, password string) error
+	// Recognizes reports whether the hash was produced by this hasher.
+	Recognizes(hashedPassword string) bool
+	// NeedsRehash reports whether the hash was produced with outdated parameters.
+	NeedsRehash(hashedPassword string) bool
+}
+
+// migratingHasher hashes with the preferred hasher and still verifies hashes of
+// the legacy hashers, which are always reported as needing a rehash.
+type migratingHasher struct {
+	preferred PasswordHasher
+	legacy    []PasswordHasher
+}
+
+var (
+	passwordHasher     PasswordHasher
+	passwordHasherOnce sync.Once
+
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
+
+// NewPasswordHasher returns a hasher that creates hashes with preferred
+// and verifies hashes created by preferred or any of the legacy hashers.
+func NewPasswordHasher(preferred PasswordHasher, legacy ...PasswordHasher) PasswordHasher {
+	return migratingHasher{
+		preferred: preferred,
+		legacy:    legacy,
 	}
+}
 
-	// return hex-encoded string with salt appended to password
-	hashedPW := string(bhash)
+func (m migratingHasher) Hash(password string) (string, error) {
+	return m.preferred.Hash(password)
+}
 
-	return hashedPW, nil
+func (m migratingHasher) Compare(hashedPassword, password string) error {
+	for _, hasher := range append([]PasswordHasher{m.preferred}, m.legacy...) {
+		if hasher.Recognizes(hashedPassword) {
+			return hasher.Compare(hashedPassword, password)
+		}
+	}
+
+	return ErrUnknownHashFormat
+}
+
+func (m migratingHasher) Recognizes(hashedPassword string) bool {
+	if m.preferred.Recognizes(hashedPassword) {
+		return true
+	}
+
+	for _, hasher := range m.legacy {
+		if hasher.Recognizes(hashedPassword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m migratingHasher) NeedsRehash(hashedPassword string) bool {
+	if !m.preferred.Recognizes(hashedPassword) {
+		return true
+	}
+
+	return m.preferred.NeedsRehash(hashedPassword)
+}
+
+// SetPasswordHasher replaces the hasher used by HashPassword, ComparePasswords and NeedsRehash.
+func SetPasswordHasher(hasher PasswordHasher) {
+	// mark the default as built, so currentHasher never replaces this hasher
+	passwordHasherOnce.Do(func() {})
+	passwordHasher = hasher
+}
+
+// currentHasher defaults to argon2id with the configured parameters and keeps
+// verifying the bcrypt hashes stored before argon2id was introduced.
+func currentHasher() PasswordHasher {
+	passwordHasherOnce.Do(func() {
+		argon2Cfg := configs.Runtime.API.Argon2
+		passwordHasher = NewPasswordHasher(
+			NewArgon2idHasher(argon2Cfg.Memory, argon2Cfg.Iterations, argon2Cfg.Parallelism),
+			NewBcryptHasher(configs.Runtime.API.BCryptSalt),
+		)
+	})
+
+	return passwordHasher
+}
+
+// HashPassword generates a secure hash of a password with the current password hasher.
+func HashPassword(password string) (string, error) {
+	return currentHasher().Hash(password)
 }
 
 // ComparePasswords checks if the supplied password matches the stored hashed password.
-// Returns true if they match or an error if there's a problem in the comparison process.
+// Returns nil if they match or an error if they don't or the hash can't be verified.
 func ComparePasswords(storedPassword, suppliedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(storedPassword), []byte(suppliedPassword))
+	return currentHasher().Compare(storedPassword, suppliedPassword)
+}
+
+// NeedsRehash reports whether the stored hash should be replaced by a fresh
+// hash of the password, because its algorithm or parameters are outdated.
+func NeedsRehash(storedPassword string) bool {
+	return currentHasher().NeedsRehash(storedPassword)
+}
+
+// CompareDummyPassword takes as long as ComparePasswords against a real hash,
+// so a login for an unknown email can't be told apart by its response time.
+func CompareDummyPassword(suppliedPassword string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = HashPassword("cats-social-dummy-password")
+	})
+
+	_ = ComparePasswords(dummyHash, suppliedPassword)
 }
@@ -0,0 +1,41 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"cats-social/common/configs"
+)
+
+const opaqueTokenLength = 32
+
+// GenerateOpaqueToken returns a random URL-safe token together with its SHA-256 hash.
+// Only the hash should be persisted, the token itself is handed to the client once.
+func GenerateOpaqueToken() (string, string, error) {
+	b := make([]byte, opaqueTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of an opaque token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshTokenExpiry returns the expiry time of a refresh token issued now.
+func RefreshTokenExpiry() time.Time {
+	return time.Now().Add(time.Duration(configs.Runtime.API.JWT.RefreshExpire) * time.Second)
+}
+
+// PasswordResetTokenExpiry returns the expiry time of a password reset token issued now.
+func PasswordResetTokenExpiry() time.Time {
+	return time.Now().Add(time.Duration(configs.Runtime.API.PasswordResetExpire) * time.Second)
+}
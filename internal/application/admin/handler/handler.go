@@ -0,0 +1,430 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/application/admin/service"
+	"cats-social/internal/domain"
+)
+
+const (
+	catIDFromParam    = "catID"
+	matchIDFromParam  = "matchID"
+	reportIDFromParam = "reportID"
+)
+
+type adminHandler struct {
+	adminService service.AdminServiceContract
+}
+
+func NewAdminHandler(
+	router fiber.Router,
+	jwtMiddleware, adminMiddleware fiber.Handler,
+	adminService service.AdminServiceContract,
+) {
+	handler := adminHandler{
+		adminService: adminService,
+	}
+
+	adminRouter := router.Group("/admin")
+
+	adminRouter.Use(jwtMiddleware, adminMiddleware)
+	adminRouter.Get("/users", handler.ListUsers)
+	adminRouter.Delete("/cats/:"+catIDFromParam, handler.DeleteCat)
+	adminRouter.Delete("/matches/:"+matchIDFromParam, handler.CancelMatch)
+	adminRouter.Get("/reports", handler.ListReports)
+	adminRouter.Post("/reports/:"+reportIDFromParam+"/resolve", handler.ResolveReport)
+}
+
+func (h adminHandler) ListUsers(c *fiber.Ctx) error {
+	callerInfo := "[adminHandler.ListUsers]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	query, res := &domain.UserQueryParam{}, baseResponse{}
+	if err := c.QueryParser(query); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := query.Validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	users, err := h.adminService.ListUsers(userCtx, *query)
+	if err != nil {
+		l.Error("error listing users",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	usersRes := make([]userResponse, len(users))
+	for i, user := range users {
+		roles := make([]string, len(user.Roles))
+		for j, role := range user.Roles {
+			roles[j] = string(role)
+		}
+
+		usersRes[i] = userResponse{
+			ID:        user.ID.String(),
+			Email:     user.Email,
+			Name:      user.Name,
+			Verified:  user.Verified,
+			Roles:     roles,
+			Suspended: user.Suspended,
+			CreatedAt: user.CreatedAt.Format(time.DateOnly),
+		}
+	}
+
+	res = baseResponse{
+		Message: successListUsersMessage,
+		Data:    usersRes,
+	}
+	return c.JSON(res)
+}
+
+func (h adminHandler) DeleteCat(c *fiber.Ctx) error {
+	callerInfo := "[adminHandler.DeleteCat]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	catID, err := ulid.Parse(c.Params(catIDFromParam))
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	res := baseResponse{}
+	err = h.adminService.DeleteCat(userCtx, catID)
+	switch {
+	case errors.Is(err, domain.ErrCatNotFound):
+		l.Error("cat not found",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.NotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case err != nil:
+		l.Error("error deleting cat",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successDeleteCatMessage,
+	}
+
+	return c.JSON(res)
+}
+
+func (h adminHandler) CancelMatch(c *fiber.Ctx) error {
+	callerInfo := "[adminHandler.CancelMatch]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	matchID, err := ulid.Parse(c.Params(matchIDFromParam))
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	res := baseResponse{}
+	err = h.adminService.CancelMatch(userCtx, matchID)
+	switch {
+	case errors.Is(err, domain.ErrMatchNotFound):
+		l.Error("match not found",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.NotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case errors.Is(err, domain.ErrMatchNotValid):
+		l.Error("match not valid",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+
+	case err != nil:
+		l.Error("error cancelling match",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successCancelMatchMessage,
+	}
+
+	return c.JSON(res)
+}
+
+func (h adminHandler) ListReports(c *fiber.Ctx) error {
+	callerInfo := "[adminHandler.ListReports]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	query, res := &domain.ReportQueryParam{}, baseResponse{}
+	if err := c.QueryParser(query); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := query.Validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	reports, err := h.adminService.ListReports(userCtx, *query)
+	if err != nil {
+		l.Error("error listing reports",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	reportsRes := make([]reportResponse, len(reports))
+	for i, report := range reports {
+		reportsRes[i] = reportResponse{
+			ID:         report.ID.String(),
+			ReporterID: report.ReporterID.String(),
+			TargetType: string(report.TargetType),
+			TargetID:   report.TargetID.String(),
+			Reason:     string(report.Reason),
+			Details:    report.Details,
+			Status:     string(report.Status),
+			Action:     string(report.Action),
+			CreatedAt:  report.CreatedAt.Format(time.RFC3339),
+		}
+		if !report.ResolvedAt.IsZero() {
+			reportsRes[i].ResolvedBy = report.ResolvedBy.String()
+			reportsRes[i].ResolvedAt = report.ResolvedAt.Format(time.RFC3339)
+		}
+	}
+
+	res = baseResponse{
+		Message: successListReportsMessage,
+		Data:    reportsRes,
+	}
+	return c.JSON(res)
+}
+
+func (h adminHandler) ResolveReport(c *fiber.Ctx) error {
+	callerInfo := "[adminHandler.ResolveReport]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	reportID, err := ulid.Parse(c.Params(reportIDFromParam))
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	req, res := &resolveReportRequest{}, baseResponse{}
+	if err = c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err = req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	err = h.adminService.ResolveReport(userCtx, reportID, userData.ID, req.Action)
+	switch {
+	case errors.Is(err, domain.ErrReportNotFound):
+		l.Error("report not found",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: reportNotFoundMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case errors.Is(err, domain.ErrReportTargetNotFound):
+		l.Error("report target not found",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: reportTargetNotFoundMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusConflict).JSON(res)
+
+	case errors.Is(err, domain.ErrReportAlreadyResolved):
+		l.Error("report already resolved",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: reportAlreadyResolvedMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusConflict).JSON(res)
+
+	case errors.Is(err, domain.ErrReportActionNotValid):
+		l.Error("report action not valid",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+
+	case err != nil:
+		l.Error("error resolving report",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successResolveReportMessage,
+	}
+
+	return c.JSON(res)
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"errors"
+
+	"cats-social/internal/domain"
+)
+
+const (
+	reportNotFoundMessage        = "Report not found"
+	reportTargetNotFoundMessage  = "Reported content no longer exists, dismiss the report instead"
+	reportAlreadyResolvedMessage = "Report already resolved"
+
+	successListUsersMessage     = "Success"
+	successDeleteCatMessage     = "Cat deleted successfully"
+	successCancelMatchMessage   = "Match cancelled successfully"
+	successListReportsMessage   = "Success"
+	successResolveReportMessage = "Report resolved successfully"
+)
+
+type baseResponse struct {
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type userResponse struct {
+	ID        string   `json:"id"`
+	Email     string   `json:"email"`
+	Name      string   `json:"name"`
+	Verified  bool     `json:"verified"`
+	Roles     []string `json:"roles"`
+	Suspended bool     `json:"suspended"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+type reportResponse struct {
+	ID         string `json:"id"`
+	ReporterID string `json:"reporterId"`
+	TargetType string `json:"targetType"`
+	TargetID   string `json:"targetId"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+	Status     string `json:"status"`
+	Action     string `json:"action,omitempty"`
+	ResolvedBy string `json:"resolvedBy,omitempty"`
+	ResolvedAt string `json:"resolvedAt,omitempty"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type resolveReportRequest struct {
+	Action domain.ReportAction `json:"action"`
+}
+
+func (r resolveReportRequest) validate() error {
+	if r.Action == "" {
+		return errors.New("action is required")
+	}
+
+	return r.Action.Validate()
+}
@@ -0,0 +1,41 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cats-social/common/configs"
+	"cats-social/internal/application/admin/handler"
+	"cats-social/internal/application/admin/service"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
+	reportRepo "cats-social/internal/application/report/repository"
+	userRepo "cats-social/internal/application/user/repository"
+)
+
+func NewModule(
+	router fiber.Router,
+	db *pgxpool.Pool,
+	revocationRepository userRepo.RevocationRepositoryContract,
+	jwtMiddleware, adminMiddleware fiber.Handler,
+) {
+	ctxTimeout := time.Duration(configs.Runtime.App.ContextTimeout) * time.Second
+
+	userRepository := userRepo.NewAuthRepository(db)
+	tokenRepository := userRepo.NewTokenRepository(db)
+	catRepository := catRepo.NewCatRepository(db)
+	matchRepository := matchRepo.NewMatchRepository(db)
+	reportRepository := reportRepo.NewReportRepository(db)
+	adminService := service.NewAdminService(
+		ctxTimeout,
+		userRepository,
+		tokenRepository,
+		revocationRepository,
+		catRepository,
+		matchRepository,
+		reportRepository,
+	)
+	handler.NewAdminHandler(router, jwtMiddleware, adminMiddleware, adminService)
+}
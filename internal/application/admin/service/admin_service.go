@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
+	reportRepo "cats-social/internal/application/report/repository"
+	userRepo "cats-social/internal/application/user/repository"
+	"cats-social/internal/domain"
+)
+
+type AdminService struct {
+	userRepository       userRepo.AuthRepositoryContract
+	tokenRepository      userRepo.TokenRepositoryContract
+	revocationRepository userRepo.RevocationRepositoryContract
+	catRepository        catRepo.CatRepositoryContract
+	matchRepository      matchRepo.MatchRepositoryContract
+	reportRepository     reportRepo.ReportRepositoryContract
+	contextTimeout       time.Duration
+}
+
+func NewAdminService(
+	timeout time.Duration,
+	userRepository userRepo.AuthRepositoryContract,
+	tokenRepository userRepo.TokenRepositoryContract,
+	revocationRepository userRepo.RevocationRepositoryContract,
+	catRepository catRepo.CatRepositoryContract,
+	matchRepository matchRepo.MatchRepositoryContract,
+	reportRepository reportRepo.ReportRepositoryContract,
+) *AdminService {
+	adminService := &AdminService{
+		userRepository:       userRepository,
+		tokenRepository:      tokenRepository,
+		revocationRepository: revocationRepository,
+		catRepository:        catRepository,
+		matchRepository:      matchRepository,
+		reportRepository:     reportRepository,
+		contextTimeout:       timeout,
+	}
+
+	return adminService
+}
+
+func (a AdminService) ListUsers(ctx context.Context, query domain.UserQueryParam) ([]domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AdminService.ListUsers]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	users, err := a.userRepository.List(ctx, query)
+	if err != nil {
+		l.Error("error list users", zap.Error(err))
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (a AdminService) DeleteCat(ctx context.Context, catID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	_, err := a.deleteCat(ctx, catID)
+	return err
+}
+
+// deleteCat soft deletes the cat within the given transaction, or its own one if none is given.
+func (a AdminService) deleteCat(ctx context.Context, catID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AdminService.deleteCat]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var tx pgx.Tx
+	if len(txs) != 0 {
+		tx = txs[0]
+	}
+
+	// no owner filter, admins can delete any cat
+	cats, err := a.catRepository.Get(ctx, ulid.ULID{}, domain.QueryParam{
+		ID: catID,
+	}, false)
+	if err != nil {
+		l.Error("error get cat", zap.Error(err))
+		return tx, err
+	}
+
+	if len(cats) != 1 {
+		err = domain.ErrCatNotFound
+		l.Info("error get cat", zap.Error(err))
+		return tx, err
+	}
+
+	tx, err = a.catRepository.Delete(ctx, catID, txs...)
+	if err != nil {
+		l.Error("error delete cat", zap.Error(err))
+		return tx, err
+	}
+
+	return tx, nil
+}
+
+// CancelMatch withdraws a pending match or undoes an approved one,
+// in which case both cats become available for matching again.
+func (a AdminService) CancelMatch(ctx context.Context, matchID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AdminService.CancelMatch]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.matchRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	tx, err = a.cancelMatch(ctx, matchID, tx)
+	if err != nil {
+		return err
+	}
+
+	err = a.matchRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// cancelMatch withdraws the match within tx, see CancelMatch.
+func (a AdminService) cancelMatch(ctx context.Context, matchID ulid.ULID, tx pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AdminService.cancelMatch]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	detailMatch, err := a.matchRepository.Get(ctx, matchID)
+	if err != nil {
+		l.Error("error get match", zap.Error(err))
+		return tx, err
+	}
+
+	if !detailMatch.Match.DeletedAt.IsZero() {
+		err = domain.ErrMatchNotValid
+		l.Error("error check match", zap.Error(err))
+		return tx, err
+	}
+
+	matchedCats := make([]domain.Cat, 0, 2)
+	for _, catID := range []ulid.ULID{detailMatch.MatchCatID, detailMatch.UserCatID} {
+		cats, err := a.catRepository.Get(ctx, ulid.ULID{}, domain.QueryParam{
+			ID: catID,
+		}, false)
+		if err != nil {
+			l.Error("error get cat", zap.Error(err))
+			return tx, err
+		}
+
+		if len(cats) == 1 && cats[0].HasMatched {
+			matchedCats = append(matchedCats, cats[0])
+		}
+	}
+
+	// both cats are only flagged as matched once the match was approved
+	if len(matchedCats) == 2 {
+		for _, cat := range matchedCats {
+			cat.HasMatched = false
+			_, tx, err = a.catRepository.Update(ctx, cat, tx)
+			if err != nil {
+				l.Error("error update cat", zap.Error(err))
+				return tx, err
+			}
+		}
+	}
+
+	tx, err = a.matchRepository.Delete(ctx, matchID, tx)
+	if err != nil {
+		l.Error("error delete match", zap.Error(err))
+		return tx, err
+	}
+
+	return tx, nil
+}
+
+func (a AdminService) ListReports(ctx context.Context, query domain.ReportQueryParam) ([]domain.Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AdminService.ListReports]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	reports, err := a.reportRepository.List(ctx, query)
+	if err != nil {
+		l.Error("error list reports", zap.Error(err))
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// ResolveReport applies the moderator's action to the reported content and closes the report.
+// Cats can only be hidden and matches withdrawn from reports about them, suspending works for
+// every report and targets the reported user, the owner of the cat or the sender of the match.
+// The action and closing the report share one transaction that locks the report, so a report
+// resolved by two moderators at once is acted on only once and the later one gets a conflict.
+func (a AdminService) ResolveReport(ctx context.Context, reportID, moderatorID ulid.ULID, action domain.ReportAction) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AdminService.ResolveReport]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.reportRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	report, err := a.reportRepository.GetForUpdate(ctx, reportID, tx)
+	if err != nil {
+		l.Error("error get report", zap.Error(err))
+		return err
+	}
+
+	if report.Status != domain.ReportStatusOpen {
+		err = domain.ErrReportAlreadyResolved
+		l.Error("error check report status", zap.Error(err))
+		return err
+	}
+
+	var suspendedUserID ulid.ULID
+	switch action {
+	case domain.ReportActionHideCat:
+		if report.TargetType != domain.ReportTargetCat {
+			err = domain.ErrReportActionNotValid
+			l.Error("error check report action", zap.Error(err))
+			return err
+		}
+		tx, err = a.deleteCat(ctx, report.TargetID, tx)
+	case domain.ReportActionWithdrawMatch:
+		if report.TargetType != domain.ReportTargetMatch {
+			err = domain.ErrReportActionNotValid
+			l.Error("error check report action", zap.Error(err))
+			return err
+		}
+		tx, err = a.cancelMatch(ctx, report.TargetID, tx)
+	case domain.ReportActionSuspendUser:
+		suspendedUserID, err = a.reportedUser(ctx, report)
+		if err == nil {
+			tx, err = a.suspendUser(ctx, suspendedUserID, tx)
+		}
+	}
+	if err != nil {
+		// content already removed by its owner or another moderator can only be dismissed
+		if errors.Is(err, domain.ErrCatNotFound) ||
+			errors.Is(err, domain.ErrMatchNotFound) ||
+			errors.Is(err, domain.ErrMatchNotValid) ||
+			errors.Is(err, domain.UserNotFoundError) {
+			err = domain.ErrReportTargetNotFound
+		}
+		l.Error("error apply report action", zap.Error(err))
+		return err
+	}
+
+	report.Action = action
+	report.ResolvedBy = moderatorID
+	tx, err = a.reportRepository.Resolve(ctx, report, tx)
+	if err != nil {
+		l.Error("error resolve report", zap.Error(err))
+		return err
+	}
+
+	err = a.reportRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	// access tokens are revoked outside the database transaction, only once the suspension is stored
+	if action == domain.ReportActionSuspendUser {
+		err = a.revocationRepository.RevokeAll(ctx, suspendedUserID)
+		if err != nil {
+			l.Error("error revoke access tokens", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reportedUser returns the user responsible for the reported content.
+func (a AdminService) reportedUser(ctx context.Context, report domain.Report) (ulid.ULID, error) {
+	callerInfo := "[AdminService.reportedUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	switch report.TargetType {
+	case domain.ReportTargetCat:
+		cats, err := a.catRepository.Get(ctx, ulid.ULID{}, domain.QueryParam{
+			ID: report.TargetID,
+		}, false)
+		if err != nil {
+			l.Error("error get cat", zap.Error(err))
+			return ulid.ULID{}, err
+		}
+
+		if len(cats) != 1 {
+			return ulid.ULID{}, domain.ErrReportTargetNotFound
+		}
+
+		return cats[0].UserID, nil
+	case domain.ReportTargetMatch:
+		detailMatch, err := a.matchRepository.Get(ctx, report.TargetID)
+		if err != nil {
+			l.Error("error get match", zap.Error(err))
+			return ulid.ULID{}, err
+		}
+
+		return detailMatch.Issuer.ID, nil
+	default:
+		return report.TargetID, nil
+	}
+}
+
+// suspendUser keeps the user from logging in, ends every session and withdraws
+// the match requests they can no longer answer. Access tokens still have to be
+// revoked once tx is committed.
+func (a AdminService) suspendUser(ctx context.Context, userID ulid.ULID, tx pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AdminService.suspendUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.userRepository.Suspend(ctx, userID, tx)
+	if err != nil {
+		l.Error("error suspend user", zap.Error(err))
+		return tx, err
+	}
+
+	tx, err = a.matchRepository.DeletePendingByUser(ctx, userID, tx)
+	if err != nil {
+		l.Error("error delete pending matches", zap.Error(err))
+		return tx, err
+	}
+
+	tx, err = a.tokenRepository.RevokeAllByUser(ctx, userID, tx)
+	if err != nil {
+		l.Error("error revoke refresh tokens", zap.Error(err))
+		return tx, err
+	}
+
+	return tx, nil
+}
+
+var _ AdminServiceContract = (*AdminService)(nil)
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/oklog/ulid/v2"
+
+	"cats-social/internal/domain"
+)
+
+type AdminServiceContract interface {
+	ListUsers(ctx context.Context, query domain.UserQueryParam) ([]domain.User, error)
+	DeleteCat(ctx context.Context, catID ulid.ULID) error
+	CancelMatch(ctx context.Context, matchID ulid.ULID) error
+	ListReports(ctx context.Context, query domain.ReportQueryParam) ([]domain.Report, error)
+	ResolveReport(ctx context.Context, reportID, moderatorID ulid.ULID, action domain.ReportAction) error
+}
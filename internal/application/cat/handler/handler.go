@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,13 +19,19 @@ import (
 
 const (
 	catIDFromParam = "catID"
+
+	mergePatchContentType = "application/merge-patch+json"
 )
 
 type catHandler struct {
 	catService service.CatServiceContract
 }
 
-func NewCatHandler(router fiber.Router, jwtMiddleware fiber.Handler, catService service.CatServiceContract) {
+func NewCatHandler(
+	router fiber.Router,
+	jwtMiddleware, verifiedMiddleware fiber.Handler,
+	catService service.CatServiceContract,
+) {
 	handler := catHandler{
 		catService: catService,
 	}
@@ -31,8 +40,10 @@ func NewCatHandler(router fiber.Router, jwtMiddleware fiber.Handler, catService
 
 	catRouter.Use(jwtMiddleware)
 	catRouter.Get("", handler.ListCats)
-	catRouter.Post("", handler.AddCat)
+	catRouter.Post("", verifiedMiddleware, handler.AddCat)
+	catRouter.Get("/:"+catIDFromParam, handler.GetCat)
 	catRouter.Put("/:"+catIDFromParam, handler.UpdateCat)
+	catRouter.Patch("/:"+catIDFromParam, handler.PatchCat)
 	catRouter.Delete("/:"+catIDFromParam, handler.DeleteCat)
 }
 
@@ -71,7 +82,7 @@ func (h catHandler) ListCats(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(res)
 	}
 
-	cats, err := h.catService.ListCats(userCtx, userData.ID, *query)
+	cats, meta, err := h.catService.ListCats(userCtx, userData.ID, *query)
 	if err != nil {
 		l.Error("error listing cats",
 			zap.Error(err),
@@ -97,16 +108,140 @@ func (h catHandler) ListCats(c *fiber.Ctx) error {
 			ImageUrls:   cat.ImageUrls,
 			HasMatched:  cat.HasMatched,
 			CreatedAt:   cat.CreatedAt.Format(time.DateOnly),
+			Snippet:     cat.Snippet,
 		}
 	}
 
 	res = baseResponse{
 		Message: successListCatMessage,
 		Data:    catsRes,
+		Meta:    newPageMetaResponse(meta),
 	}
 	return c.JSON(res)
 }
 
+// GetCat returns a single cat, the response carries an ETag of its body so clients
+// can revalidate with If-None-Match and get a 304 when nothing changed.
+func (h catHandler) GetCat(c *fiber.Ctx) error {
+	callerInfo := "[catHandler.GetCat]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	catID, err := ulid.Parse(c.Params(catIDFromParam))
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	cat, err := h.catService.GetCat(userCtx, userData.ID, catID)
+	switch {
+	case errors.Is(err, domain.ErrCatNotFound):
+		l.Info("cat not found",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.NotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case err != nil:
+		l.Error("error getting cat",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successGetCatMessage,
+		Data: catDetailResponse{
+			ID:          cat.ID.String(),
+			Name:        cat.Name,
+			Race:        cat.Race,
+			Sex:         cat.Sex,
+			AgeInMonth:  cat.AgeInMonth,
+			Description: cat.Description,
+			ImageUrls:   cat.ImageUrls,
+			HasMatched:  cat.HasMatched,
+			Owner: catOwnerResponse{
+				ID:   cat.Owner.ID.String(),
+				Name: cat.Owner.Name,
+			},
+			MatchStatus: catMatchStatusResponse{
+				PendingIncoming: cat.PendingIncoming,
+				PendingOutgoing: cat.PendingOutgoing,
+			},
+			CreatedAt: cat.CreatedAt.Format(time.DateOnly),
+			UpdatedAt: serverWallClock(cat.UpdatedAt).UTC().Format(time.RFC3339),
+		},
+	}
+
+	body, err := c.App().Config().JSONEncoder(res)
+	if err != nil {
+		l.Error("error encoding response",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	// the body depends on the caller through the block filter, so only private caches may keep it
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
+
+// serverWallClock returns the instant of a time read from a cats TIMESTAMP column. Those are
+// written with the server's wall clock, which pgx reads back labelled as UTC.
+func serverWallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
+
+// etagMatches reports whether an If-None-Match header holds the etag, comparing weakly
+// as RFC 9110 asks for GET requests.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (h catHandler) AddCat(c *fiber.Ctx) error {
 	callerInfo := "[catHandler.AddCat]"
 
@@ -295,6 +430,119 @@ func (h catHandler) UpdateCat(c *fiber.Ctx) error {
 	return c.JSON(res)
 }
 
+// PatchCat updates the fields present in a JSON merge patch (RFC 7396), plain JSON bodies
+// are accepted as well.
+func (h catHandler) PatchCat(c *fiber.Ctx) error {
+	callerInfo := "[catHandler.PatchCat]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	mediaType, _, _ := strings.Cut(c.Get(fiber.HeaderContentType), ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType != mergePatchContentType && mediaType != fiber.MIMEApplicationJSON {
+		l.Error("unsupported content type",
+			zap.String("contentType", mediaType),
+		)
+		res := baseResponse{
+			Message: unsupportedMediaTypeMessage,
+			Data: fiber.Map{
+				"error": "content type must be " + mergePatchContentType,
+			},
+		}
+		return c.Status(http.StatusUnsupportedMediaType).JSON(res)
+	}
+
+	catID, err := ulid.Parse(c.Params(catIDFromParam))
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	req, err := parseCatPatch(c.Body(), c.App().Config().JSONDecoder)
+	if err == nil {
+		err = req.validate()
+	}
+	if err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	cat, err := h.catService.PatchCat(userCtx, userData.ID, catID, req.toDomain())
+	switch {
+	case errors.Is(err, domain.ErrCatNotFound):
+		l.Info("cat not found",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.NotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case errors.Is(err, domain.ErrCatAlreadyMatched):
+		l.Info("cat already requested to match",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+
+	case err != nil:
+		l.Error("error patching cat",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successPatchCatMessage,
+		Data: listCatResponse{
+			ID:          cat.ID.String(),
+			Name:        cat.Name,
+			Race:        cat.Race,
+			Sex:         cat.Sex,
+			AgeInMonth:  cat.AgeInMonth,
+			Description: cat.Description,
+			ImageUrls:   cat.ImageUrls,
+			HasMatched:  cat.HasMatched,
+			CreatedAt:   cat.CreatedAt.Format(time.DateOnly),
+		},
+	}
+
+	return c.JSON(res)
+}
+
 func (h catHandler) DeleteCat(c *fiber.Ctx) error {
 	callerInfo := "[catHandler.DeleteCat]"
 
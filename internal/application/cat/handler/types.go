@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -13,13 +14,40 @@ import (
 const (
 	successAddCatMessage    = "Cat added successfully"
 	successListCatMessage   = "Success"
+	successGetCatMessage    = "Success"
 	successUpdateCatMessage = "Cat updated successfully"
-	successDeleteCatMessage = "Cat deleted successfully"
+	successPatchCatMessage  = "Cat updated successfully"
+
+	unsupportedMediaTypeMessage = "Unsupported Media Type"
+	successDeleteCatMessage     = "Cat deleted successfully"
 )
 
 type baseResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
+	Message string            `json:"message"`
+	Data    any               `json:"data,omitempty"`
+	Meta    *pageMetaResponse `json:"meta,omitempty"`
+}
+
+type pageMetaResponse struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int    `json:"total"`
+	HasNext    bool   `json:"hasNext"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func newPageMetaResponse(meta domain.PageMeta) *pageMetaResponse {
+	res := &pageMetaResponse{
+		Limit:   meta.Limit,
+		Offset:  meta.Offset,
+		Total:   meta.Total,
+		HasNext: meta.HasNext,
+	}
+	if !meta.Next.IsZero() {
+		res.NextCursor = meta.Next.Encode()
+	}
+
+	return res
 }
 
 type imageUrls []string
@@ -56,36 +84,128 @@ type catRequest struct {
 }
 
 func (a catRequest) validate() error {
-	var errs error
+	errs := multierr.Combine(
+		validateName(a.Name),
+		a.Race.Validate(),
+		a.Sex.Validate(),
+		validateAgeInMonth(a.AgeInMonth),
+		validateDescription(a.Description),
+		a.ImageUrls.validate(),
+	)
 
-	if a.Name != "" && len(a.Name) < 1 || len(a.Name) > 30 {
-		errs = multierr.Append(errs, errors.New("name must be between 1 and 30 characters"))
-	}
-	if a.Name == "" {
-		errs = multierr.Append(errs, errors.New("name is required"))
+	if errs != nil {
+		return errs
 	}
 
-	if err := a.Race.Validate(); err != nil {
-		errs = multierr.Append(errs, err)
+	return nil
+}
+
+func validateName(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len(name) > 30 {
+		return errors.New("name must be between 1 and 30 characters")
 	}
 
-	if err := a.Sex.Validate(); err != nil {
-		errs = multierr.Append(errs, err)
+	return nil
+}
+
+func validateAgeInMonth(ageInMonth int) error {
+	if ageInMonth < 1 || ageInMonth > 120082 {
+		return errors.New("age must be between 1 and 120082 months")
 	}
 
-	if a.AgeInMonth < 1 || a.AgeInMonth > 120082 {
-		errs = multierr.Append(errs, errors.New("age must be between 1 and 120082 months"))
+	return nil
+}
+
+func validateDescription(description string) error {
+	if description == "" {
+		return errors.New("description is required")
+	}
+	if len(description) > 200 {
+		return errors.New("description must be between 1 and 200 characters")
 	}
 
-	if a.Description != "" && len(a.Description) < 1 || len(a.Description) > 200 {
-		errs = multierr.Append(errs, errors.New("description must be between 1 and 200 characters"))
+	return nil
+}
+
+// catPatchRequest is a JSON merge patch (RFC 7396) of a cat, nil fields were absent from the patch.
+// Every field of a cat is required, so none of them can be removed with null.
+type catPatchRequest struct {
+	Name        *string
+	Race        *domain.CatRace
+	Sex         *domain.CatSex
+	AgeInMonth  *int
+	Description *string
+	ImageUrls   imageUrls
+}
+
+func parseCatPatch(body []byte, decode func([]byte, any) error) (catPatchRequest, error) {
+	var (
+		req     catPatchRequest
+		members map[string]json.RawMessage
+	)
+
+	if err := decode(body, &members); err != nil || members == nil {
+		return req, errors.New("merge patch must be a JSON object")
 	}
-	if a.Description == "" {
-		errs = multierr.Append(errs, errors.New("description is required"))
+
+	var errs error
+	for key, value := range members {
+		if string(value) == "null" {
+			errs = multierr.Append(errs, fmt.Errorf("%s can't be removed", key))
+			continue
+		}
+
+		var target any
+		switch key {
+		case "name":
+			target = &req.Name
+		case "race":
+			target = &req.Race
+		case "sex":
+			target = &req.Sex
+		case "ageInMonth":
+			target = &req.AgeInMonth
+		case "description":
+			target = &req.Description
+		case "imageUrls":
+			target = &req.ImageUrls
+		default:
+			errs = multierr.Append(errs, fmt.Errorf("unknown field: %s", key))
+			continue
+		}
+
+		if err := decode(value, target); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("invalid %s: %w", key, err))
+		}
 	}
 
-	if err := a.ImageUrls.validate(); err != nil {
-		errs = multierr.Append(errs, err)
+	return req, errs
+}
+
+// validate checks the fields present in the patch only.
+func (a catPatchRequest) validate() error {
+	var errs error
+
+	if a.Name != nil {
+		errs = multierr.Append(errs, validateName(*a.Name))
+	}
+	if a.Race != nil {
+		errs = multierr.Append(errs, a.Race.Validate())
+	}
+	if a.Sex != nil {
+		errs = multierr.Append(errs, a.Sex.Validate())
+	}
+	if a.AgeInMonth != nil {
+		errs = multierr.Append(errs, validateAgeInMonth(*a.AgeInMonth))
+	}
+	if a.Description != nil {
+		errs = multierr.Append(errs, validateDescription(*a.Description))
+	}
+	if a.ImageUrls != nil {
+		errs = multierr.Append(errs, a.ImageUrls.validate())
 	}
 
 	if errs != nil {
@@ -95,6 +215,17 @@ func (a catRequest) validate() error {
 	return nil
 }
 
+func (a catPatchRequest) toDomain() domain.CatPatch {
+	return domain.CatPatch{
+		Name:        a.Name,
+		Race:        a.Race,
+		Sex:         a.Sex,
+		AgeInMonth:  a.AgeInMonth,
+		Description: a.Description,
+		ImageUrls:   a.ImageUrls,
+	}
+}
+
 type addCatResponse struct {
 	ID        string `json:"id"`
 	CreatedAt string `json:"createdAt"`
@@ -110,4 +241,30 @@ type listCatResponse struct {
 	ImageUrls   imageUrls      `json:"imageUrls"`
 	HasMatched  bool           `json:"hasMatched"`
 	CreatedAt   string         `json:"createdAt"`
+	Snippet     string         `json:"snippet,omitempty"`
+}
+
+type catOwnerResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type catMatchStatusResponse struct {
+	PendingIncoming int `json:"pendingIncoming"`
+	PendingOutgoing int `json:"pendingOutgoing"`
+}
+
+type catDetailResponse struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Race        domain.CatRace         `json:"race"`
+	Sex         domain.CatSex          `json:"sex"`
+	AgeInMonth  int                    `json:"ageInMonth"`
+	Description string                 `json:"description"`
+	ImageUrls   imageUrls              `json:"imageUrls"`
+	HasMatched  bool                   `json:"hasMatched"`
+	Owner       catOwnerResponse       `json:"owner"`
+	MatchStatus catMatchStatusResponse `json:"matchStatus"`
+	CreatedAt   string                 `json:"createdAt"`
+	UpdatedAt   string                 `json:"updatedAt"`
 }
@@ -11,13 +11,15 @@ import (
 	catRepo "cats-social/internal/application/cat/repository"
 	"cats-social/internal/application/cat/service"
 	matchRepo "cats-social/internal/application/match/repository"
+	userRepo "cats-social/internal/application/user/repository"
 )
 
-func NewModule(router fiber.Router, db *pgxpool.Pool, jwtMiddleware fiber.Handler) {
+func NewModule(router fiber.Router, db *pgxpool.Pool, jwtMiddleware, verifiedMiddleware fiber.Handler) {
 	ctxTimeout := time.Duration(configs.Runtime.App.ContextTimeout) * time.Second
 
 	catRepository := catRepo.NewCatRepository(db)
 	matchRepository := matchRepo.NewMatchRepository(db)
-	catService := service.NewCatService(ctxTimeout, catRepository, matchRepository)
-	handler.NewCatHandler(router, jwtMiddleware, catService)
+	userRepository := userRepo.NewAuthRepository(db)
+	catService := service.NewCatService(ctxTimeout, catRepository, matchRepository, userRepository)
+	handler.NewCatHandler(router, jwtMiddleware, verifiedMiddleware, catService)
 }
@@ -165,8 +165,26 @@ func (c CatRepository) Get(
 	callerInfo := "[CatRepository.Get]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	getCatsQuery := `SELECT id, name, race, sex, age_in_month, description, user_id, has_matched, created_at, updated_at, deleted_at FROM cats`
-	getCatsQuery, params := c.getConditions(getCatsQuery, query, userID)
+	getCatsQuery, params, err := c.getConditions(`FROM cats`, query, userID)
+	if err != nil {
+		l.Error("failed to build conditions", zap.Error(err))
+		return nil, err
+	}
+	getCatsQuery, params = c.getPagination(getCatsQuery, query, params)
+
+	snippetColumn := `''`
+	if query.Search != "" && query.Highlight == domain.TrueBool {
+		params = append(params, query.Search)
+		snippetColumn = fmt.Sprintf(
+			`ts_headline('english', description, websearch_to_tsquery('english', $%d), 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2')`,
+			len(params),
+		)
+	}
+	getCatsQuery = fmt.Sprintf(
+		`SELECT id, name, race, sex, age_in_month, description, user_id, has_matched, created_at, updated_at, deleted_at, %s %s`,
+		snippetColumn,
+		getCatsQuery,
+	)
 
 	rows, err := c.db.Query(ctx, getCatsQuery, params...)
 	if err != nil {
@@ -190,6 +208,7 @@ func (c CatRepository) Get(
 			&mCat.CreatedAt,
 			&mCat.UpdatedAt,
 			&mCat.DeletedAt,
+			&mCat.Snippet,
 		)
 		if err != nil {
 			l.Error("failed to scan cat", zap.Error(err))
@@ -206,6 +225,8 @@ func (c CatRepository) Get(
 			UserID:      mCat.UserID,
 			HasMatched:  mCat.HasMatched,
 			CreatedAt:   mCat.CreatedAt,
+			UpdatedAt:   mCat.UpdatedAt,
+			Snippet:     mCat.Snippet,
 		})
 	}
 
@@ -227,7 +248,7 @@ func (c CatRepository) Get(
 	return cats, nil
 }
 
-func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryParam, userID ulid.ULID) (string, []any) {
+func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryParam, userID ulid.ULID) (string, []any, error) {
 	params := make([]any, 0)
 	conditions := make([]string, 0)
 
@@ -237,31 +258,17 @@ func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryPar
 		conditions = append(conditions, fmt.Sprintf("id = $%d", len(params)))
 	}
 
-	if queryParam.Race != "" {
-		params = append(params, queryParam.Race)
-		conditions = append(conditions, fmt.Sprintf("race = $%d", len(params)))
-	}
-
-	if queryParam.Sex != "" {
-		params = append(params, queryParam.Sex)
-		conditions = append(conditions, fmt.Sprintf("sex = $%d", len(params)))
+	filterConditions, params, err := c.compileFilter(queryParam.Filter, params)
+	if err != nil {
+		return "", nil, err
 	}
+	conditions = append(conditions, filterConditions...)
 
 	if queryParam.HasMatched != "" {
 		params = append(params, queryParam.HasMatched)
 		conditions = append(conditions, fmt.Sprintf("has_matched = $%d", len(params)))
 	}
 
-	if queryParam.AgeInMonth != "" && len(queryParam.AgeInMonth) > 1 {
-		comparison := queryParam.AgeInMonth[:1]
-		if comparison != ">" && comparison != "<" && comparison != "=" {
-			comparison = "="
-		}
-
-		params = append(params, queryParam.AgeInMonth[1:])
-		conditions = append(conditions, fmt.Sprintf("age_in_month %s $%d", comparison, len(params)))
-	}
-
 	if queryParam.Owned != "" {
 		params = append(params, userID)
 
@@ -276,14 +283,198 @@ func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryPar
 	}
 
 	if queryParam.Search != "" {
-		params = append(params, fmt.Sprintf("%%%s%%", queryParam.Search))
-		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(params)))
+		params = append(params, queryParam.Search)
+		conditions = append(conditions, fmt.Sprintf("search_vector @@ websearch_to_tsquery('english', $%d)", len(params)))
+	}
+
+	if !queryParam.After.IsZero() {
+		var keysetCondition string
+		keysetCondition, params = c.keysetCondition(queryParam.After, params)
+		conditions = append(conditions, keysetCondition)
+	}
+
+	// cats of users in a block with the caller are hidden, whoever blocked whom
+	if userID != emptyID {
+		params = append(params, userID)
+		conditions = append(conditions, fmt.Sprintf(
+			"NOT EXISTS (SELECT 1 FROM user_blocks b WHERE (b.blocker_id = $%[1]d AND b.blocked_id = cats.user_id) OR (b.blocked_id = $%[1]d AND b.blocker_id = cats.user_id))",
+			len(params),
+		))
 	}
 
 	conditions = append(conditions, "deleted_at IS NULL")
 
+	if len(conditions) > 0 {
+		getQuery = fmt.Sprintf("%s WHERE %s", getQuery, strings.Join(conditions, " AND "))
+	}
+
+	return getQuery, params, nil
+}
+
+// filterColumns whitelists the columns a filter can reach.
+var filterColumns = map[domain.FilterField]string{
+	domain.FilterAgeInMonth: "age_in_month",
+	domain.FilterRace:       "race",
+	domain.FilterSex:        "sex",
+	domain.FilterCreatedAt:  "created_at",
+}
+
+var filterOperators = map[domain.CompareOp]string{
+	domain.OpEqual:          "=",
+	domain.OpNotEqual:       "<>",
+	domain.OpGreater:        ">",
+	domain.OpGreaterOrEqual: ">=",
+	domain.OpLess:           "<",
+	domain.OpLessOrEqual:    "<=",
+}
+
+// compileFilter turns every predicate into a condition, values are always passed as parameters.
+func (c CatRepository) compileFilter(filter domain.Filter, params []any) ([]string, []any, error) {
+	conditions := make([]string, 0, len(filter.Predicates))
+
+	for _, predicate := range filter.Predicates {
+		var condition string
+
+		switch p := predicate.(type) {
+		case domain.IntComparison:
+			condition, params = c.compileComparison(p.Field, p.Op, p.Value, params)
+		case domain.TimeComparison:
+			condition, params = c.compileComparison(p.Field, p.Op, p.Value, params)
+		case domain.ValueSet:
+			condition, params = c.compileValueSet(p, params)
+		}
+
+		if condition == "" {
+			return nil, nil, fmt.Errorf("unsupported filter: %+v", predicate)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	return conditions, params, nil
+}
+
+func (c CatRepository) compileValueSet(set domain.ValueSet, params []any) (string, []any) {
+	column, ok := filterColumns[set.Field]
+	if !ok {
+		return "", params
+	}
+
+	params = append(params, set.Values)
+	// race and sex are enums, comparing as text saves registering their array types
+	condition := fmt.Sprintf("%s::text = ANY($%d::text[])", column, len(params))
+	if set.Negate {
+		condition = fmt.Sprintf("NOT (%s)", condition)
+	}
+
+	return condition, params
+}
+
+func (c CatRepository) compileComparison(field domain.FilterField, op domain.CompareOp, value any, params []any) (string, []any) {
+	column, ok := filterColumns[field]
+	if !ok {
+		return "", params
+	}
+
+	operator, ok := filterOperators[op]
+	if !ok {
+		return "", params
+	}
+
+	params = append(params, value)
+	return fmt.Sprintf("%s %s $%d", column, operator, len(params)), params
+}
+
+// sortColumns whitelists the columns a listing can be ordered by, race is ordered by its name
+// rather than its position in the enum so alphabetical views work.
+var sortColumns = map[domain.SortField]string{
+	domain.SortCreatedAt:  "created_at",
+	domain.SortAgeInMonth: "age_in_month",
+	domain.SortName:       "name",
+	domain.SortRace:       "cat_race_text(race)",
+}
+
+func sortDirection(desc bool) string {
+	if desc {
+		return "DESC"
+	}
+
+	return "ASC"
+}
+
+// orderBy breaks ties by id in the direction of the first key, so a single key sort can walk
+// its (column, id) index either way.
+func (c CatRepository) orderBy(sort domain.Sort) string {
+	terms := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		terms = append(terms, fmt.Sprintf("%s %s", sortColumns[key.Field], sortDirection(key.Desc)))
+	}
+	terms = append(terms, "id "+sortDirection(sort[0].Desc))
+
+	return strings.Join(terms, ", ")
+}
+
+// keysetCondition selects the rows after the cursor in the order of its sort. A row comparison
+// is used when every key runs the same way, since postgres can serve it from an index, otherwise
+// the comparison is spelled out key by key.
+func (c CatRepository) keysetCondition(after domain.Cursor, params []any) (string, []any) {
+	columns := make([]string, 0, len(after.Sort)+1)
+	placeholders := make([]string, 0, len(after.Sort)+1)
+	operators := make([]string, 0, len(after.Sort)+1)
+	sameDirection := true
+
+	for i, key := range after.Sort {
+		params = append(params, after.Values[i])
+		columns = append(columns, sortColumns[key.Field])
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(params)))
+		operators = append(operators, keysetOperator(key.Desc))
+		sameDirection = sameDirection && key.Desc == after.Sort[0].Desc
+	}
+	params = append(params, after.ID)
+	columns = append(columns, "id")
+	placeholders = append(placeholders, fmt.Sprintf("$%d", len(params)))
+	operators = append(operators, keysetOperator(after.Sort[0].Desc))
+
+	if sameDirection {
+		return fmt.Sprintf("(%s) %s (%s)",
+			strings.Join(columns, ", "), operators[0], strings.Join(placeholders, ", "),
+		), params
+	}
+
+	terms := make([]string, len(columns))
+	for i := range columns {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = %s", columns[j], placeholders[j]))
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", columns[i], operators[i], placeholders[i]))
+		terms[i] = "(" + strings.Join(parts, " AND ") + ")"
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")", params
+}
+
+func keysetOperator(desc bool) string {
+	if desc {
+		return "<"
+	}
+
+	return ">"
+}
+
+func (c CatRepository) getPagination(getQuery string, queryParam domain.QueryParam, params []any) (string, []any) {
 	filter := make([]string, 0)
-	filter = append(filter, "ORDER BY created_at DESC")
+	if len(queryParam.SortKeys) > 0 {
+		filter = append(filter, "ORDER BY "+c.orderBy(queryParam.SortKeys))
+	} else if queryParam.Search != "" {
+		params = append(params, queryParam.Search)
+		filter = append(filter, fmt.Sprintf(
+			"ORDER BY ts_rank(search_vector, websearch_to_tsquery('english', $%d)) DESC, %s",
+			len(params),
+			c.orderBy(domain.DefaultSort),
+		))
+	} else {
+		filter = append(filter, "ORDER BY "+c.orderBy(domain.DefaultSort))
+	}
 
 	if queryParam.Limit != 0 {
 		params = append(params, queryParam.Limit)
@@ -295,10 +486,6 @@ func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryPar
 		filter = append(filter, fmt.Sprintf("OFFSET $%d", len(params)))
 	}
 
-	if len(conditions) > 0 {
-		getQuery = fmt.Sprintf("%s WHERE %s", getQuery, strings.Join(conditions, " AND "))
-	}
-
 	if len(filter) > 0 {
 		getQuery = fmt.Sprintf("%s %s", getQuery, strings.Join(filter, " "))
 	}
@@ -306,6 +493,30 @@ func (c CatRepository) getConditions(getQuery string, queryParam domain.QueryPar
 	return getQuery, params
 }
 
+func (c CatRepository) Count(ctx context.Context, userID ulid.ULID, query domain.QueryParam) (int, error) {
+	callerInfo := "[CatRepository.Count]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	// the total covers the whole filter, not only the rows after the cursor
+	query.After = domain.Cursor{}
+
+	countQuery := `SELECT COUNT(*) FROM cats`
+	countQuery, params, err := c.getConditions(countQuery, query, userID)
+	if err != nil {
+		l.Error("failed to build conditions", zap.Error(err))
+		return 0, err
+	}
+
+	var total int
+	err = c.db.QueryRow(ctx, countQuery, params...).Scan(&total)
+	if err != nil {
+		l.Error("failed to count cats", zap.Error(err))
+		return 0, err
+	}
+
+	return total, nil
+}
+
 func (c CatRepository) getImages(ctx context.Context, cats []domain.Cat) (err error) {
 	callerInfo := "[CatRepository.getImages]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
@@ -481,34 +692,96 @@ func (c CatRepository) deleteCatImages(ctx context.Context, tx pgx.Tx, catID uli
 	return nil
 }
 
-func (c CatRepository) Delete(ctx context.Context, catID ulid.ULID) error {
+func (c CatRepository) Delete(ctx context.Context, catID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
 	callerInfo := "[CatRepository.Delete]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	tx, err := c.db.Begin(ctx)
-	if err != nil {
-		l.Error("failed to begin transaction", zap.Error(err))
-		return err
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = c.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
 	}
-	defer func() {
-		_ = tx.Rollback(ctx)
-	}()
 
 	deleteQuery := `UPDATE cats SET deleted_at = $1 WHERE id = $2`
 
 	_, err = tx.Exec(ctx, deleteQuery, time.Now(), catID)
 	if err != nil {
 		l.Error("failed to delete cat", zap.Error(err))
-		return err
+		return tx, err
 	}
 
-	err = tx.Commit(ctx)
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+// DeleteByUser soft deletes every cat owned by the user together with their images.
+func (c CatRepository) DeleteByUser(ctx context.Context, userID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[CatRepository.DeleteByUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = c.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	now := time.Now()
+
+	deleteImagesQuery := `UPDATE cat_images SET deleted_at = $1, updated_at = $1
+		WHERE deleted_at IS NULL AND cat_id IN (SELECT id FROM cats WHERE user_id = $2 AND deleted_at IS NULL)`
+	_, err = tx.Exec(ctx, deleteImagesQuery, now, userID)
 	if err != nil {
-		l.Error("failed to commit transaction", zap.Error(err))
-		return err
+		l.Error("failed to delete cat images", zap.Error(err))
+		return tx, err
 	}
 
-	return nil
+	deleteCatsQuery := `UPDATE cats SET deleted_at = $1, updated_at = $1 WHERE user_id = $2 AND deleted_at IS NULL`
+	_, err = tx.Exec(ctx, deleteCatsQuery, now, userID)
+	if err != nil {
+		l.Error("failed to delete cats", zap.Error(err))
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
 }
 
 var _ CatRepositoryContract = (*CatRepository)(nil)
@@ -12,6 +12,8 @@ import (
 type CatRepositoryContract interface {
 	Create(ctx context.Context, cat domain.Cat) (domain.Cat, error)
 	Get(ctx context.Context, userID ulid.ULID, query domain.QueryParam, withImages bool) ([]domain.Cat, error)
+	Count(ctx context.Context, userID ulid.ULID, query domain.QueryParam) (int, error)
 	Update(ctx context.Context, cat domain.Cat, tx ...pgx.Tx) (domain.Cat, pgx.Tx, error)
-	Delete(ctx context.Context, catID ulid.ULID) error
+	Delete(ctx context.Context, catID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	DeleteByUser(ctx context.Context, userID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
 }
@@ -19,6 +19,7 @@ type cat struct {
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   sql.NullTime
+	Snippet     string
 }
 
 type catImages struct {
@@ -10,12 +10,14 @@ import (
 	"cats-social/common/logger"
 	catRepo "cats-social/internal/application/cat/repository"
 	matchRepo "cats-social/internal/application/match/repository"
+	userRepo "cats-social/internal/application/user/repository"
 	"cats-social/internal/domain"
 )
 
 type CatService struct {
 	catRepository   catRepo.CatRepositoryContract
 	matchRepository matchRepo.MatchRepositoryContract
+	userRepository  userRepo.AuthRepositoryContract
 	contextTimeout  time.Duration
 }
 
@@ -23,10 +25,12 @@ func NewCatService(
 	timeout time.Duration,
 	catRepository catRepo.CatRepositoryContract,
 	matchRepository matchRepo.MatchRepositoryContract,
+	userRepository userRepo.AuthRepositoryContract,
 ) *CatService {
 	catService := &CatService{
 		catRepository:   catRepository,
 		matchRepository: matchRepository,
+		userRepository:  userRepository,
 		contextTimeout:  timeout,
 	}
 
@@ -49,20 +53,88 @@ func (c CatService) AddCat(ctx context.Context, cat domain.Cat) (domain.Cat, err
 	return cat, nil
 }
 
-func (c CatService) ListCats(ctx context.Context, userID ulid.ULID, query domain.QueryParam) ([]domain.Cat, error) {
+func (c CatService) ListCats(ctx context.Context, userID ulid.ULID, query domain.QueryParam) ([]domain.Cat, domain.PageMeta, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.contextTimeout)
 	defer cancel()
 
 	callerInfo := "[CatService.ListCats]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	cats, err := c.catRepository.Get(ctx, userID, query, true)
+	meta := domain.PageMeta{
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	// one extra row tells whether another page follows
+	page := query
+	page.Limit++
+
+	cats, err := c.catRepository.Get(ctx, userID, page, true)
 	if err != nil {
 		l.Error("error list cats", zap.Error(err))
-		return cats, err
+		return cats, meta, err
+	}
+
+	if len(cats) > query.Limit {
+		cats = cats[:query.Limit]
+		meta.HasNext = true
+
+		// search results ordered by rank are paged through offset instead
+		if len(query.SortKeys) > 0 {
+			meta.Next = domain.NewCatCursor(query.SortKeys, cats[len(cats)-1])
+		}
+	}
+
+	meta.Total, err = c.catRepository.Count(ctx, userID, query)
+	if err != nil {
+		l.Error("error count cats", zap.Error(err))
+		return cats, meta, err
+	}
+
+	return cats, meta, nil
+}
+
+func (c CatService) GetCat(ctx context.Context, userID, catID ulid.ULID) (domain.CatDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[CatService.GetCat]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	cats, err := c.catRepository.Get(ctx, userID, domain.QueryParam{
+		ID: catID,
+	}, true)
+	if err != nil {
+		l.Error("error get cat", zap.Error(err))
+		return domain.CatDetail{}, err
+	}
+
+	if len(cats) != 1 {
+		err = domain.ErrCatNotFound
+		l.Info("error get cat", zap.Error(err))
+		return domain.CatDetail{}, err
+	}
+
+	owner, err := c.userRepository.Get(ctx, cats[0].UserID)
+	if err != nil {
+		l.Error("error get owner", zap.Error(err))
+		return domain.CatDetail{}, err
+	}
+
+	incoming, outgoing, err := c.matchRepository.CountPendingByCat(ctx, catID)
+	if err != nil {
+		l.Error("error count pending matches", zap.Error(err))
+		return domain.CatDetail{}, err
 	}
 
-	return cats, nil
+	detail := domain.CatDetail{
+		Cat:             cats[0],
+		Owner:           owner,
+		PendingIncoming: incoming,
+		PendingOutgoing: outgoing,
+	}
+
+	return detail, nil
 }
 
 func (c CatService) UpdateCat(ctx context.Context, updatedCat domain.Cat) (domain.Cat, error) {
@@ -92,7 +164,7 @@ func (c CatService) UpdateCat(ctx context.Context, updatedCat domain.Cat) (domai
 
 	if cat.Sex != updatedCat.Sex {
 		foundMatches := make([]domain.DetailMatch, 0)
-		foundMatches, err = c.matchRepository.GetDetailMatches(ctx, updatedCat.UserID)
+		foundMatches, err = c.matchRepository.GetDetailMatches(ctx, updatedCat.UserID, domain.MatchQueryParam{})
 		if err != nil {
 			l.Error("error get matches", zap.Error(err))
 			return cat, err
@@ -111,7 +183,7 @@ func (c CatService) UpdateCat(ctx context.Context, updatedCat domain.Cat) (domai
 	cat.Description = updatedCat.Description
 	cat.ImageUrls = updatedCat.ImageUrls
 
-	foundMatches, err := c.matchRepository.GetDetailMatches(ctx, updatedCat.UserID)
+	foundMatches, err := c.matchRepository.GetDetailMatches(ctx, updatedCat.UserID, domain.MatchQueryParam{})
 	if err != nil {
 		l.Error("error get matches", zap.Error(err))
 		return cat, err
@@ -131,6 +203,63 @@ func (c CatService) UpdateCat(ctx context.Context, updatedCat domain.Cat) (domai
 	return cat, nil
 }
 
+// PatchCat applies a partial update to a cat owned by the user. Only a sex change is refused
+// while the user has matches, images are replaced only when the patch carries them.
+func (c CatService) PatchCat(ctx context.Context, userID, catID ulid.ULID, patch domain.CatPatch) (domain.Cat, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[CatService.PatchCat]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	cats, err := c.catRepository.Get(ctx, userID, domain.QueryParam{
+		ID:    catID,
+		Owned: domain.TrueBool,
+	}, true)
+	if err != nil {
+		l.Error("error get cat", zap.Error(err))
+		return domain.Cat{}, err
+	}
+
+	if len(cats) != 1 {
+		err = domain.ErrCatNotFound
+		l.Info("error get cat", zap.Error(err))
+		return domain.Cat{}, err
+	}
+
+	cat := cats[0]
+	if patch.IsEmpty() {
+		return cat, nil
+	}
+
+	if patch.Sex != nil && *patch.Sex != cat.Sex {
+		foundMatches, err := c.matchRepository.GetDetailMatches(ctx, userID, domain.MatchQueryParam{})
+		if err != nil {
+			l.Error("error get matches", zap.Error(err))
+			return cat, err
+		}
+		if len(foundMatches) > 0 {
+			err = domain.ErrCatAlreadyMatched
+			l.Error("cat already requested to match", zap.Error(err))
+			return cat, err
+		}
+	}
+
+	patch.Apply(&cat)
+
+	// the repository keeps the stored images when none are given
+	updatedCat := cat
+	updatedCat.ImageUrls = patch.ImageUrls
+
+	_, _, err = c.catRepository.Update(ctx, updatedCat)
+	if err != nil {
+		l.Error("error update cat", zap.Error(err))
+		return cat, err
+	}
+
+	return cat, nil
+}
+
 func (c CatService) DeleteCat(ctx context.Context, cat domain.Cat) error {
 	ctx, cancel := context.WithTimeout(ctx, c.contextTimeout)
 	defer cancel()
@@ -154,7 +283,7 @@ func (c CatService) DeleteCat(ctx context.Context, cat domain.Cat) error {
 		return err
 	}
 
-	err = c.catRepository.Delete(ctx, cat.ID)
+	_, err = c.catRepository.Delete(ctx, cat.ID)
 	if err != nil {
 		l.Error("error delete cat", zap.Error(err))
 		return err
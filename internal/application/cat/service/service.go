@@ -10,7 +10,9 @@ import (
 
 type CatServiceContract interface {
 	AddCat(ctx context.Context, cat domain.Cat) (domain.Cat, error)
-	ListCats(ctx context.Context, userID ulid.ULID, query domain.QueryParam) ([]domain.Cat, error)
+	ListCats(ctx context.Context, userID ulid.ULID, query domain.QueryParam) ([]domain.Cat, domain.PageMeta, error)
+	GetCat(ctx context.Context, userID, catID ulid.ULID) (domain.CatDetail, error)
+	PatchCat(ctx context.Context, userID, catID ulid.ULID, patch domain.CatPatch) (domain.Cat, error)
 	UpdateCat(ctx context.Context, cat domain.Cat) (domain.Cat, error)
 	DeleteCat(ctx context.Context, cat domain.Cat) error
 }
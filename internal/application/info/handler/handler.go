@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"cats-social/common/configs"
+	"cats-social/common/security"
 )
 
 type infoHandler struct {
@@ -23,6 +24,8 @@ func NewInfoHandler(router fiber.Router, db *pgxpool.Pool) {
 
 	infoRouter.Get("/version", handler.Version)
 	infoRouter.Get("/health", handler.Health)
+
+	router.Get("/.well-known/jwks.json", handler.JWKS)
 }
 
 func (h infoHandler) Version(c *fiber.Ctx) error {
@@ -81,3 +84,11 @@ func (h infoHandler) Health(c *fiber.Ctx) error {
 
 	return c.JSON(res)
 }
+
+// JWKS publishes the keys that verify our access tokens, so other services
+// can check them without holding the signing key.
+func (h infoHandler) JWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+
+	return c.JSON(security.PublicJWKS())
+}
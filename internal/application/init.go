@@ -5,17 +5,30 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"cats-social/common/configs"
+	"cats-social/internal/application/admin"
 	"cats-social/internal/application/cat"
 	"cats-social/internal/application/info"
 	"cats-social/internal/application/match"
+	"cats-social/internal/application/report"
 	"cats-social/internal/application/user"
+	userRepo "cats-social/internal/application/user/repository"
 )
 
-func New(server *fiber.App, db *pgxpool.Pool, jwtMiddleware fiber.Handler) {
+func New(
+	server *fiber.App,
+	db *pgxpool.Pool,
+	revocationRepository userRepo.RevocationRepositoryContract,
+	sessionRepository userRepo.SessionRepositoryContract,
+	jwtMiddleware fiber.Handler,
+	verifiedMiddleware fiber.Handler,
+	adminMiddleware fiber.Handler,
+) {
 	v1 := server.Group(configs.Runtime.API.BaseURL)
 
 	info.NewModule(v1, db)
-	user.NewModule(v1, db)
-	cat.NewModule(v1, db, jwtMiddleware)
-	match.NewModule(v1, db, jwtMiddleware)
+	user.NewModule(v1, db, revocationRepository, sessionRepository, jwtMiddleware)
+	cat.NewModule(v1, db, jwtMiddleware, verifiedMiddleware)
+	match.NewModule(v1, db, jwtMiddleware, verifiedMiddleware)
+	report.NewModule(v1, db, jwtMiddleware, verifiedMiddleware)
+	admin.NewModule(v1, db, revocationRepository, jwtMiddleware, adminMiddleware)
 }
@@ -22,7 +22,11 @@ type matchHandler struct {
 	matchService service.MatchServiceContract
 }
 
-func NewMatchHandler(router fiber.Router, jwtMiddleware fiber.Handler, matchService service.MatchServiceContract) {
+func NewMatchHandler(
+	router fiber.Router,
+	jwtMiddleware, verifiedMiddleware fiber.Handler,
+	matchService service.MatchServiceContract,
+) {
 	handler := matchHandler{
 		matchService: matchService,
 	}
@@ -30,7 +34,7 @@ func NewMatchHandler(router fiber.Router, jwtMiddleware fiber.Handler, matchServ
 	matchRouter := router.Group("/cat/match")
 
 	matchRouter.Use(jwtMiddleware)
-	matchRouter.Post("", handler.NewMatch)
+	matchRouter.Post("", verifiedMiddleware, handler.NewMatch)
 	matchRouter.Get("", handler.GetMatch)
 	matchRouter.Post("/approve", handler.ApproveMatch)
 	matchRouter.Post("/reject", handler.RejectMatch)
@@ -94,7 +98,8 @@ func (h matchHandler) NewMatch(c *fiber.Ctx) error {
 
 	case errors.Is(err, domain.ErrCatGenderNotMatch),
 		errors.Is(err, domain.ErrCatAlreadyMatched),
-		errors.Is(err, domain.ErrCatSameOwner):
+		errors.Is(err, domain.ErrCatSameOwner),
+		errors.Is(err, domain.ErrUserBlocked):
 		l.Error(err.Error(),
 			zap.Error(err),
 		)
@@ -134,7 +139,34 @@ func (h matchHandler) GetMatch(c *fiber.Ctx) error {
 
 	userData := c.Locals(domain.UserFromToken).(domain.User)
 
-	detailMatches, err := h.matchService.GetMatch(userCtx, userData.ID)
+	query := &domain.MatchQueryParam{}
+	if err := c.QueryParser(query); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := query.Validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	detailMatches, meta, err := h.matchService.GetMatch(userCtx, userData.ID, *query)
 	if err != nil {
 		l.Error("error getting match",
 			zap.Error(err),
@@ -187,6 +219,7 @@ func (h matchHandler) GetMatch(c *fiber.Ctx) error {
 	res := baseResponse{
 		Message: successGetMatchMessage,
 		Data:    detailMatchesRes,
+		Meta:    newPageMetaResponse(meta),
 	}
 
 	return c.JSON(res)
@@ -18,8 +18,31 @@ const (
 )
 
 type baseResponse struct {
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
+	Message string            `json:"message"`
+	Data    any               `json:"data,omitempty"`
+	Meta    *pageMetaResponse `json:"meta,omitempty"`
+}
+
+type pageMetaResponse struct {
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	Total      int    `json:"total"`
+	HasNext    bool   `json:"hasNext"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+func newPageMetaResponse(meta domain.PageMeta) *pageMetaResponse {
+	res := &pageMetaResponse{
+		Limit:   meta.Limit,
+		Offset:  meta.Offset,
+		Total:   meta.Total,
+		HasNext: meta.HasNext,
+	}
+	if !meta.Next.IsZero() {
+		res.NextCursor = meta.Next.Encode()
+	}
+
+	return res
 }
 
 type matchRequest struct {
@@ -14,12 +14,12 @@ import (
 	userRepo "cats-social/internal/application/user/repository"
 )
 
-func NewModule(router fiber.Router, db *pgxpool.Pool, jwtMiddleware fiber.Handler) {
+func NewModule(router fiber.Router, db *pgxpool.Pool, jwtMiddleware, verifiedMiddleware fiber.Handler) {
 	ctxTimeout := time.Duration(configs.Runtime.App.ContextTimeout) * time.Second
 
 	catRepository := catRepo.NewCatRepository(db)
 	userRepository := userRepo.NewAuthRepository(db)
 	matchRepository := matchRepo.NewMatchRepository(db)
 	matchService := service.NewMatchService(ctxTimeout, matchRepository, catRepository, userRepository)
-	handler.NewMatchHandler(router, jwtMiddleware, matchService)
+	handler.NewMatchHandler(router, jwtMiddleware, verifiedMiddleware, matchService)
 }
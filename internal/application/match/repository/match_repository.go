@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -16,6 +17,11 @@ import (
 	"cats-social/internal/domain"
 )
 
+// userPairLock is the advisory lock key of two users, the same whichever user comes first.
+// Sending a request and blocking take it, so a block can't slip in between the block check
+// of a request and its insert, nor a request between a block and the withdrawal of the pending ones.
+const userPairLock = `hashtextextended(encode(LEAST(%[1]s, %[2]s), 'hex') || encode(GREATEST(%[1]s, %[2]s), 'hex'), 0)`
+
 type MatchRepository struct {
 	db *pgxpool.Pool
 }
@@ -53,6 +59,43 @@ func (m MatchRepository) NewMatch(ctx context.Context, dMatch domain.Match) (dom
 		},
 	}
 
+	lockQuery := `SELECT pg_advisory_xact_lock(` + fmt.Sprintf(userPairLock, "r.user_id", "i.user_id") + `)
+		FROM cats r, cats i WHERE r.id = $1 AND i.id = $2`
+	_, err = tx.Exec(ctx, lockQuery, mMatch.MatchCatID, mMatch.UserCatID)
+	if err != nil {
+		l.Error("error locking users",
+			zap.Error(err),
+		)
+		return dMatch, err
+	}
+
+	// cats of blocked users are hidden from the caller, holding the lock this also covers
+	// a block made while the request was sent
+	var blocked bool
+	blockedQuery := `SELECT EXISTS (
+		SELECT 1
+		FROM cats r
+		JOIN cats i ON i.id = $2
+		JOIN user_blocks b ON (b.blocker_id = r.user_id AND b.blocked_id = i.user_id)
+			OR (b.blocker_id = i.user_id AND b.blocked_id = r.user_id)
+		WHERE r.id = $1
+	)`
+	err = tx.QueryRow(ctx, blockedQuery, mMatch.MatchCatID, mMatch.UserCatID).Scan(&blocked)
+	if err != nil {
+		l.Error("error checking block",
+			zap.Error(err),
+		)
+		return dMatch, err
+	}
+
+	if blocked {
+		err = domain.ErrUserBlocked
+		l.Error("error checking block",
+			zap.Error(err),
+		)
+		return dMatch, err
+	}
+
 	insertQuery := `INSERT INTO matches (id, match_cat_id, user_cat_id, message, created_at, updated_at, deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
 
 	_, err = tx.Exec(
@@ -106,17 +149,39 @@ func (m MatchRepository) HasMatched(ctx context.Context, dMatch domain.Match) (b
 	return true, nil
 }
 
-func (m MatchRepository) GetDetailMatches(ctx context.Context, userID ulid.ULID) ([]domain.DetailMatch, error) {
+// userMatchesQuery selects the matches a user issued or received, GetDetailMatches and Count
+// share it so the total always agrees with the listing.
+const userMatchesQuery = `FROM matches m
+		JOIN cats r ON m.match_cat_id = r.id
+		JOIN cats i ON m.user_cat_id = i.id
+		WHERE (r.user_id = $1 OR i.user_id = $1) AND m.deleted_at IS NULL`
+
+func (m MatchRepository) GetDetailMatches(ctx context.Context, userID ulid.ULID, query domain.MatchQueryParam) ([]domain.DetailMatch, error) {
 	callerInfo := "[MatchRepository.GetDetailMatches]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
 	getMatchesQuery := `SELECT m.id, m.match_cat_id, m.user_cat_id, m.message, m.created_at, r.user_id as receiver_id, i.user_id as issuer_id
-		FROM matches m 
-		JOIN cats r ON m.match_cat_id = r.id
-		JOIN cats i ON m.user_cat_id = i.id
-		WHERE (r.user_id = $1 OR i.user_id = $2) AND m.deleted_at IS NULL ORDER BY m.created_at DESC`
+		` + userMatchesQuery
+	params := []any{userID}
+
+	if !query.After.IsZero() {
+		params = append(params, query.After.Values[0], query.After.ID)
+		getMatchesQuery = fmt.Sprintf("%s AND (m.created_at, m.id) < ($%d, $%d)", getMatchesQuery, len(params)-1, len(params))
+	}
 
-	rows, err := m.db.Query(ctx, getMatchesQuery, userID, userID)
+	getMatchesQuery += " ORDER BY m.created_at DESC, m.id DESC"
+
+	if query.Limit != 0 {
+		params = append(params, query.Limit)
+		getMatchesQuery = fmt.Sprintf("%s LIMIT $%d", getMatchesQuery, len(params))
+	}
+
+	if query.Offset != 0 {
+		params = append(params, query.Offset)
+		getMatchesQuery = fmt.Sprintf("%s OFFSET $%d", getMatchesQuery, len(params))
+	}
+
+	rows, err := m.db.Query(ctx, getMatchesQuery, params...)
 	if err != nil {
 		l.Error("error getting data",
 			zap.Error(err),
@@ -171,6 +236,78 @@ func (m MatchRepository) GetDetailMatches(ctx context.Context, userID ulid.ULID)
 	return matches, nil
 }
 
+func (m MatchRepository) Count(ctx context.Context, userID ulid.ULID) (int, error) {
+	callerInfo := "[MatchRepository.Count]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	countQuery := `SELECT COUNT(*) ` + userMatchesQuery
+
+	var total int
+	err := m.db.QueryRow(ctx, countQuery, userID).Scan(&total)
+	if err != nil {
+		l.Error("error counting data",
+			zap.Error(err),
+		)
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// HasApprovedMatch reports whether a cat of userID and a cat of otherUserID were matched and
+// the match was approved, in either direction.
+func (m MatchRepository) HasApprovedMatch(ctx context.Context, userID, otherUserID ulid.ULID) (bool, error) {
+	callerInfo := "[MatchRepository.HasApprovedMatch]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT EXISTS (
+		SELECT 1
+		FROM matches m
+		JOIN cats r ON m.match_cat_id = r.id
+		JOIN cats i ON m.user_cat_id = i.id
+		WHERE ((r.user_id = $1 AND i.user_id = $2) OR (r.user_id = $2 AND i.user_id = $1))
+		AND r.has_matched AND i.has_matched AND m.deleted_at IS NULL
+	)`
+
+	var approved bool
+	err := m.db.QueryRow(ctx, query, userID, otherUserID).Scan(&approved)
+	if err != nil {
+		l.Error("error checking data",
+			zap.Error(err),
+		)
+		return false, err
+	}
+
+	return approved, nil
+}
+
+// CountPendingByCat counts the match requests of a cat that were not approved yet,
+// incoming requests were sent to the cat and outgoing ones were sent by it.
+func (m MatchRepository) CountPendingByCat(ctx context.Context, catID ulid.ULID) (int, int, error) {
+	callerInfo := "[MatchRepository.CountPendingByCat]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	countQuery := `SELECT
+			COUNT(*) FILTER (WHERE m.match_cat_id = $1),
+			COUNT(*) FILTER (WHERE m.user_cat_id = $1)
+		FROM matches m
+		JOIN cats r ON m.match_cat_id = r.id
+		JOIN cats i ON m.user_cat_id = i.id
+		WHERE (m.match_cat_id = $1 OR m.user_cat_id = $1)
+		AND NOT (r.has_matched AND i.has_matched) AND m.deleted_at IS NULL`
+
+	var incoming, outgoing int
+	err := m.db.QueryRow(ctx, countQuery, catID).Scan(&incoming, &outgoing)
+	if err != nil {
+		l.Error("error counting data",
+			zap.Error(err),
+		)
+		return 0, 0, err
+	}
+
+	return incoming, outgoing, nil
+}
+
 func (m MatchRepository) Get(ctx context.Context, matchID ulid.ULID) (domain.DetailMatch, error) {
 	callerInfo := "[MatchRepository.Get]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
@@ -277,20 +414,145 @@ func (m MatchRepository) DeleteExceptApproved(
 	return tx, nil
 }
 
-func (m MatchRepository) Delete(ctx context.Context, matchID ulid.ULID) error {
-	callerInfo := "[MatchRepository.Delete]"
+// DeletePendingByUser soft deletes every match request involving the user's cats
+// that has not been approved yet, approved matches are kept as history.
+func (m MatchRepository) DeletePendingByUser(ctx context.Context, userID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[MatchRepository.DeletePendingByUser]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	tx, err := m.db.Begin(ctx)
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = m.db.Begin(ctx)
+		if err != nil {
+			l.Error("error starting transaction",
+				zap.Error(err),
+			)
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	deleteQuery := `UPDATE matches SET deleted_at = $1
+		FROM cats as r, cats as i
+		WHERE matches.match_cat_id = r.id AND matches.user_cat_id = i.id
+		AND (r.user_id = $2 OR i.user_id = $3)
+		AND NOT (r.has_matched AND i.has_matched) AND matches.deleted_at IS NULL`
+
+	_, err = tx.Exec(ctx, deleteQuery, time.Now(), userID, userID)
 	if err != nil {
-		l.Error("error starting transaction",
+		l.Error("error deleting data",
 			zap.Error(err),
 		)
-		return err
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+// DeletePendingBetween soft deletes the match requests between the cats of two users
+// that have not been approved yet, in either direction.
+func (m MatchRepository) DeletePendingBetween(
+	ctx context.Context,
+	userID, otherUserID ulid.ULID,
+	txs ...pgx.Tx,
+) (pgx.Tx, error) {
+	callerInfo := "[MatchRepository.DeletePendingBetween]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = m.db.Begin(ctx)
+		if err != nil {
+			l.Error("error starting transaction",
+				zap.Error(err),
+			)
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	// requests sent from now on see the block, see userPairLock
+	lockQuery := `SELECT pg_advisory_xact_lock(` + fmt.Sprintf(userPairLock, "$1::bytea", "$2::bytea") + `)`
+	_, err = tx.Exec(ctx, lockQuery, userID, otherUserID)
+	if err != nil {
+		l.Error("error locking users",
+			zap.Error(err),
+		)
+		return tx, err
+	}
+
+	deleteQuery := `UPDATE matches SET deleted_at = $1
+		FROM cats as r, cats as i
+		WHERE matches.match_cat_id = r.id AND matches.user_cat_id = i.id
+		AND ((r.user_id = $2 AND i.user_id = $3) OR (r.user_id = $3 AND i.user_id = $2))
+		AND NOT (r.has_matched AND i.has_matched) AND matches.deleted_at IS NULL`
+
+	_, err = tx.Exec(ctx, deleteQuery, time.Now(), userID, otherUserID)
+	if err != nil {
+		l.Error("error deleting data",
+			zap.Error(err),
+		)
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (m MatchRepository) Delete(ctx context.Context, matchID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[MatchRepository.Delete]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = m.db.Begin(ctx)
+		if err != nil {
+			l.Error("error starting transaction",
+				zap.Error(err),
+			)
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
 	}
-	defer func() {
-		_ = tx.Rollback(ctx)
-	}()
 
 	deleteQuery := `UPDATE matches SET deleted_at = $1 WHERE id = $2`
 
@@ -299,16 +561,18 @@ func (m MatchRepository) Delete(ctx context.Context, matchID ulid.ULID) error {
 		l.Error("error deleting data",
 			zap.Error(err),
 		)
-		return err
+		return tx, err
 	}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		l.Error("failed to commit transaction", zap.Error(err))
-		return err
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
 	}
 
-	return nil
+	return tx, nil
 }
 
 func (m MatchRepository) TxBegin(ctx context.Context) (pgx.Tx, error) {
@@ -12,10 +12,15 @@ import (
 type MatchRepositoryContract interface {
 	NewMatch(ctx context.Context, match domain.Match) (domain.Match, error)
 	HasMatched(ctx context.Context, match domain.Match) (bool, error)
-	GetDetailMatches(ctx context.Context, userID ulid.ULID) ([]domain.DetailMatch, error)
+	GetDetailMatches(ctx context.Context, userID ulid.ULID, query domain.MatchQueryParam) ([]domain.DetailMatch, error)
+	Count(ctx context.Context, userID ulid.ULID) (int, error)
+	HasApprovedMatch(ctx context.Context, userID, otherUserID ulid.ULID) (bool, error)
+	CountPendingByCat(ctx context.Context, catID ulid.ULID) (incoming, outgoing int, err error)
 	Get(ctx context.Context, matchID ulid.ULID) (domain.DetailMatch, error)
 	DeleteExceptApproved(ctx context.Context, userID, matchID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
-	Delete(ctx context.Context, matchID ulid.ULID) error
+	DeletePendingByUser(ctx context.Context, userID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	DeletePendingBetween(ctx context.Context, userID, otherUserID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	Delete(ctx context.Context, matchID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
 	TxBegin(ctx context.Context) (pgx.Tx, error)
 	TxCommit(ctx context.Context, tx pgx.Tx) error
 }
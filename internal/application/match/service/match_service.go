@@ -44,7 +44,7 @@ func (m MatchService) NewMatch(ctx context.Context, match domain.Match, userID u
 	callerInfo := "[MatchService.NewMatch]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	// Check matchCatId is exist
+	// Check matchCatId is exist, cats of users in a block with the user are not visible
 	cats, err := m.catRepository.Get(ctx, userID, domain.QueryParam{
 		ID: match.MatchCatID,
 	}, false)
@@ -121,7 +121,7 @@ func (m MatchService) NewMatch(ctx context.Context, match domain.Match, userID u
 	return match, nil
 }
 
-func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.DetailMatch, error) {
+func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID, query domain.MatchQueryParam) ([]domain.DetailMatch, domain.PageMeta, error) {
 	ctx, cancel := context.WithTimeout(ctx, m.contextTimeout)
 	defer cancel()
 
@@ -129,12 +129,34 @@ func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
 	detailMatches := make([]domain.DetailMatch, 0)
+	meta := domain.PageMeta{
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	// get matches with user_id as issuer and receiver, one extra match tells whether another page follows
+	page := query
+	if page.Limit != 0 {
+		page.Limit++
+	}
 
-	// get matches with user_id as issuer and receiver
-	detailMatches, err := m.matchRepository.GetDetailMatches(ctx, userID)
+	detailMatches, err := m.matchRepository.GetDetailMatches(ctx, userID, page)
 	if err != nil {
 		l.Error("error get matches", zap.Error(err))
-		return detailMatches, err
+		return detailMatches, meta, err
+	}
+
+	if query.Limit != 0 && len(detailMatches) > query.Limit {
+		detailMatches = detailMatches[:query.Limit]
+		last := detailMatches[len(detailMatches)-1]
+		meta.HasNext = true
+		meta.Next = domain.Cursor{Sort: domain.DefaultSort, Values: []any{last.CreatedAt}, ID: last.ID}
+	}
+
+	meta.Total, err = m.matchRepository.Count(ctx, userID)
+	if err != nil {
+		l.Error("error count matches", zap.Error(err))
+		return detailMatches, meta, err
 	}
 
 	for i, detailMatch := range detailMatches {
@@ -143,7 +165,7 @@ func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.
 		user, err = m.userRepository.Get(ctx, detailMatch.Issuer.ID)
 		if err != nil {
 			l.Error("error get issuer", zap.Error(err))
-			return detailMatches, err
+			return detailMatches, meta, err
 		}
 		detailMatches[i].Issuer = user
 
@@ -154,12 +176,12 @@ func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.
 		}, true)
 		if err != nil {
 			l.Error("error get match cat", zap.Error(err))
-			return detailMatches, err
+			return detailMatches, meta, err
 		}
 		if len(cats) != 1 {
 			err = domain.ErrCatNotFound
 			l.Error("error get match cat", zap.Error(err))
-			return detailMatches, err
+			return detailMatches, meta, err
 		}
 		detailMatches[i].MatchCat = cats[0]
 
@@ -169,17 +191,17 @@ func (m MatchService) GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.
 		}, true)
 		if err != nil {
 			l.Error("error get user cat", zap.Error(err))
-			return detailMatches, err
+			return detailMatches, meta, err
 		}
 		if len(cats) != 1 {
 			err = domain.ErrCatNotFound
 			l.Error("error get user cat", zap.Error(err))
-			return detailMatches, err
+			return detailMatches, meta, err
 		}
 		detailMatches[i].UserCat = cats[0]
 	}
 
-	return detailMatches, nil
+	return detailMatches, meta, nil
 }
 
 func (m MatchService) ApproveMatch(ctx context.Context, matchID, userID ulid.ULID) error {
@@ -310,7 +332,7 @@ func (m MatchService) RejectMatch(ctx context.Context, matchID, userID ulid.ULID
 		return err
 	}
 
-	err = m.matchRepository.Delete(ctx, matchID)
+	_, err = m.matchRepository.Delete(ctx, matchID)
 	if err != nil {
 		l.Error("error delete match", zap.Error(err))
 		return err
@@ -366,7 +388,7 @@ func (m MatchService) DeleteMatch(ctx context.Context, matchID, userID ulid.ULID
 		return err
 	}
 
-	err = m.matchRepository.Delete(ctx, matchID)
+	_, err = m.matchRepository.Delete(ctx, matchID)
 	if err != nil {
 		l.Error("error delete match", zap.Error(err))
 		return err
@@ -10,7 +10,7 @@ import (
 
 type MatchServiceContract interface {
 	NewMatch(ctx context.Context, match domain.Match, userID ulid.ULID) (domain.Match, error)
-	GetMatch(ctx context.Context, userID ulid.ULID) ([]domain.DetailMatch, error)
+	GetMatch(ctx context.Context, userID ulid.ULID, query domain.MatchQueryParam) ([]domain.DetailMatch, domain.PageMeta, error)
 	ApproveMatch(ctx context.Context, matchID, userID ulid.ULID) error
 	RejectMatch(ctx context.Context, matchID, userID ulid.ULID) error
 	DeleteMatch(ctx context.Context, matchID, userID ulid.ULID) error
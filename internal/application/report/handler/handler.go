@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/application/report/service"
+	"cats-social/internal/domain"
+)
+
+type reportHandler struct {
+	reportService service.ReportServiceContract
+}
+
+func NewReportHandler(
+	router fiber.Router,
+	jwtMiddleware, verifiedMiddleware fiber.Handler,
+	reportService service.ReportServiceContract,
+) {
+	handler := reportHandler{
+		reportService: reportService,
+	}
+
+	reportRouter := router.Group("/report")
+
+	reportRouter.Use(jwtMiddleware, verifiedMiddleware)
+	reportRouter.Post("", handler.CreateReport)
+}
+
+func (h reportHandler) CreateReport(c *fiber.Ctx) error {
+	callerInfo := "[reportHandler.CreateReport]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	req, res := &reportRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	reportData := domain.Report{
+		ReporterID: userData.ID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	}
+
+	report, err := h.reportService.Create(userCtx, reportData)
+	switch {
+	case errors.Is(err, domain.ErrReportTargetNotFound):
+		l.Error("report target not found",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: reportTargetNotFoundMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+
+	case errors.Is(err, domain.ErrReportOwnTarget):
+		l.Error("report own target",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: reportOwnTargetMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+
+	case errors.Is(err, domain.ErrDuplicateReport):
+		l.Error("duplicate report",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: duplicateReportMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusConflict).JSON(res)
+
+	case err != nil:
+		l.Error("error creating report",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successCreateReportMessage,
+		Data: reportResponse{
+			ID:        report.ID.String(),
+			Status:    string(report.Status),
+			CreatedAt: report.CreatedAt.Format(time.RFC3339),
+		},
+	}
+
+	return c.Status(http.StatusCreated).JSON(res)
+}
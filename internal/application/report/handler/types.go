@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"errors"
+	"unicode/utf8"
+
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/multierr"
+
+	"cats-social/internal/domain"
+)
+
+const (
+	reportTargetNotFoundMessage = "Reported content not found"
+	reportOwnTargetMessage      = "You can't report yourself or your own content"
+	duplicateReportMessage      = "You already reported this content"
+
+	successCreateReportMessage = "Report submitted successfully"
+)
+
+const maxDetailsLength = 1000
+
+type baseResponse struct {
+	Message string `json:"message"`
+	Data    any    `json:"data,omitempty"`
+}
+
+type reportRequest struct {
+	TargetType domain.ReportTargetType `json:"targetType"`
+	TargetID   ulid.ULID               `json:"targetId"`
+	Reason     domain.ReportReason     `json:"reason"`
+	Details    string                  `json:"details"`
+}
+
+func (r reportRequest) validate() error {
+	var errs error
+
+	if r.TargetType == "" {
+		errs = multierr.Append(errs, errors.New("targetType is required"))
+	} else if err := r.TargetType.Validate(); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	if r.TargetID == (ulid.ULID{}) {
+		errs = multierr.Append(errs, errors.New("targetId is required"))
+	}
+
+	if r.Reason == "" {
+		errs = multierr.Append(errs, errors.New("reason is required"))
+	} else if err := r.Reason.Validate(); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	if utf8.RuneCountInString(r.Details) > maxDetailsLength {
+		errs = multierr.Append(errs, errors.New("details must be at most 1000 characters"))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+type reportResponse struct {
+	ID        string `json:"id"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"createdAt"`
+}
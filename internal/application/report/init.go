@@ -0,0 +1,27 @@
+package report
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"cats-social/common/configs"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
+	"cats-social/internal/application/report/handler"
+	reportRepo "cats-social/internal/application/report/repository"
+	"cats-social/internal/application/report/service"
+	userRepo "cats-social/internal/application/user/repository"
+)
+
+func NewModule(router fiber.Router, db *pgxpool.Pool, jwtMiddleware, verifiedMiddleware fiber.Handler) {
+	ctxTimeout := time.Duration(configs.Runtime.App.ContextTimeout) * time.Second
+
+	reportRepository := reportRepo.NewReportRepository(db)
+	catRepository := catRepo.NewCatRepository(db)
+	matchRepository := matchRepo.NewMatchRepository(db)
+	userRepository := userRepo.NewAuthRepository(db)
+	reportService := service.NewReportService(ctxTimeout, reportRepository, catRepository, matchRepository, userRepository)
+	handler.NewReportHandler(router, jwtMiddleware, verifiedMiddleware, reportService)
+}
@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+const reportColumns = `id, reporter_id, target_type, target_id, reason, details, status, action, resolved_by, resolved_at, created_at`
+
+type ReportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepository(db *pgxpool.Pool) *ReportRepository {
+	return &ReportRepository{
+		db: db,
+	}
+}
+
+func (r ReportRepository) Create(ctx context.Context, dReport domain.Report) (domain.Report, error) {
+	callerInfo := "[ReportRepository.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	mReport := report{
+		ID:         id.New(),
+		ReporterID: dReport.ReporterID,
+		TargetType: string(dReport.TargetType),
+		TargetID:   dReport.TargetID,
+		Reason:     string(dReport.Reason),
+		Details:    dReport.Details,
+		Status:     string(domain.ReportStatusOpen),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	insertQuery := `INSERT INTO reports (id, reporter_id, target_type, target_id, reason, details, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := r.db.Exec(
+		ctx,
+		insertQuery,
+		mReport.ID,
+		mReport.ReporterID,
+		mReport.TargetType,
+		mReport.TargetID,
+		mReport.Reason,
+		mReport.Details,
+		mReport.Status,
+		mReport.CreatedAt,
+		mReport.UpdatedAt,
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			l.Error("report already exists", zap.Error(err))
+			return dReport, domain.ErrDuplicateReport
+		}
+		l.Error("failed to insert report", zap.Error(err))
+		return dReport, err
+	}
+
+	dReport.ID = mReport.ID
+	dReport.Status = domain.ReportStatusOpen
+	dReport.CreatedAt = mReport.CreatedAt
+	return dReport, nil
+}
+
+func (r ReportRepository) Get(ctx context.Context, reportID ulid.ULID) (domain.Report, error) {
+	callerInfo := "[ReportRepository.Get]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE id = $1`
+	dReport, err := scanReport(r.db.QueryRow(ctx, query, reportID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("report not found", zap.Error(err))
+			return domain.Report{}, domain.ErrReportNotFound
+		}
+		l.Error("failed to get report", zap.Error(err))
+		return domain.Report{}, err
+	}
+
+	return dReport, nil
+}
+
+// GetForUpdate returns the report and locks it until tx ends, so concurrent
+// moderators resolve it one after another and the later one sees it closed.
+func (r ReportRepository) GetForUpdate(ctx context.Context, reportID ulid.ULID, tx pgx.Tx) (domain.Report, error) {
+	callerInfo := "[ReportRepository.GetForUpdate]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT ` + reportColumns + ` FROM reports WHERE id = $1 FOR UPDATE`
+	dReport, err := scanReport(tx.QueryRow(ctx, query, reportID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("report not found", zap.Error(err))
+			return domain.Report{}, domain.ErrReportNotFound
+		}
+		l.Error("failed to get report", zap.Error(err))
+		return domain.Report{}, err
+	}
+
+	return dReport, nil
+}
+
+// List returns the reports matching the query, oldest first so the queue is worked in order.
+func (r ReportRepository) List(ctx context.Context, query domain.ReportQueryParam) ([]domain.Report, error) {
+	callerInfo := "[ReportRepository.List]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	params := make([]any, 0)
+	conditions := make([]string, 0)
+	listQuery := `SELECT ` + reportColumns + ` FROM reports`
+
+	if query.Status != "" {
+		params = append(params, query.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(params)))
+	}
+
+	if query.TargetType != "" {
+		params = append(params, query.TargetType)
+		conditions = append(conditions, fmt.Sprintf("target_type = $%d", len(params)))
+	}
+
+	if len(conditions) > 0 {
+		listQuery = fmt.Sprintf("%s WHERE %s", listQuery, strings.Join(conditions, " AND "))
+	}
+
+	params = append(params, query.Limit, query.Offset)
+	listQuery = fmt.Sprintf("%s ORDER BY created_at ASC LIMIT $%d OFFSET $%d", listQuery, len(params)-1, len(params))
+
+	rows, err := r.db.Query(ctx, listQuery, params...)
+	if err != nil {
+		l.Error("failed to get reports", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := make([]domain.Report, 0)
+	for rows.Next() {
+		dReport, err := scanReport(rows)
+		if err != nil {
+			l.Error("failed to scan report", zap.Error(err))
+			return nil, err
+		}
+
+		reports = append(reports, dReport)
+	}
+
+	if err = rows.Err(); err != nil {
+		l.Error("failed to iterate reports", zap.Error(err))
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// Resolve closes an open report with the moderator's action. Acting on the target
+// also closes the other open reports about the same target, dismissing doesn't.
+func (r ReportRepository) Resolve(ctx context.Context, dReport domain.Report, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[ReportRepository.Resolve]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = r.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	status := dReport.Action.Status()
+	now := time.Now()
+
+	updateQuery := `UPDATE reports SET status = $1, action = $2, resolved_by = $3, resolved_at = $4, updated_at = $4
+		WHERE id = $5 AND status = $6`
+	cmd, err := tx.Exec(ctx, updateQuery, status, dReport.Action, dReport.ResolvedBy, now, dReport.ID, domain.ReportStatusOpen)
+	if err != nil {
+		l.Error("failed to resolve report", zap.Error(err))
+		return tx, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("report already resolved")
+		return tx, domain.ErrReportAlreadyResolved
+	}
+
+	if status == domain.ReportStatusActioned {
+		updateQuery = `UPDATE reports SET status = $1, action = $2, resolved_by = $3, resolved_at = $4, updated_at = $4
+			WHERE target_type = $5 AND target_id = $6 AND status = $7`
+		_, err = tx.Exec(
+			ctx,
+			updateQuery,
+			status,
+			dReport.Action,
+			dReport.ResolvedBy,
+			now,
+			dReport.TargetType,
+			dReport.TargetID,
+			domain.ReportStatusOpen,
+		)
+		if err != nil {
+			l.Error("failed to resolve reports on the same target", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (r ReportRepository) TxBegin(ctx context.Context) (pgx.Tx, error) {
+	callerInfo := "[ReportRepository.TxBegin]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (r ReportRepository) TxCommit(ctx context.Context, tx pgx.Tx) error {
+	callerInfo := "[ReportRepository.TxCommit]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func scanReport(row pgx.Row) (domain.Report, error) {
+	var mReport report
+	err := row.Scan(
+		&mReport.ID,
+		&mReport.ReporterID,
+		&mReport.TargetType,
+		&mReport.TargetID,
+		&mReport.Reason,
+		&mReport.Details,
+		&mReport.Status,
+		&mReport.Action,
+		&mReport.ResolvedBy,
+		&mReport.ResolvedAt,
+		&mReport.CreatedAt,
+	)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	dReport := domain.Report{
+		ID:         mReport.ID,
+		ReporterID: mReport.ReporterID,
+		TargetType: domain.ReportTargetType(mReport.TargetType),
+		TargetID:   mReport.TargetID,
+		Reason:     domain.ReportReason(mReport.Reason),
+		Details:    mReport.Details,
+		Status:     domain.ReportStatus(mReport.Status),
+		Action:     domain.ReportAction(mReport.Action.String),
+		ResolvedBy: mReport.ResolvedBy,
+		ResolvedAt: mReport.ResolvedAt.Time,
+		CreatedAt:  mReport.CreatedAt,
+	}
+
+	return dReport, nil
+}
+
+var _ ReportRepositoryContract = (*ReportRepository)(nil)
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+
+	"cats-social/internal/domain"
+)
+
+type ReportRepositoryContract interface {
+	Create(ctx context.Context, report domain.Report) (domain.Report, error)
+	Get(ctx context.Context, reportID ulid.ULID) (domain.Report, error)
+	GetForUpdate(ctx context.Context, reportID ulid.ULID, tx pgx.Tx) (domain.Report, error)
+	List(ctx context.Context, query domain.ReportQueryParam) ([]domain.Report, error)
+	Resolve(ctx context.Context, report domain.Report, tx ...pgx.Tx) (pgx.Tx, error)
+	TxBegin(ctx context.Context) (pgx.Tx, error)
+	TxCommit(ctx context.Context, tx pgx.Tx) error
+}
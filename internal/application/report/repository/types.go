@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type report struct {
+	ID         ulid.ULID
+	ReporterID ulid.ULID
+	TargetType string
+	TargetID   ulid.ULID
+	Reason     string
+	Details    string
+	Status     string
+	Action     sql.NullString
+	ResolvedBy ulid.ULID
+	ResolvedAt sql.NullTime
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
+	reportRepo "cats-social/internal/application/report/repository"
+	userRepo "cats-social/internal/application/user/repository"
+	"cats-social/internal/domain"
+)
+
+type ReportService struct {
+	reportRepository reportRepo.ReportRepositoryContract
+	catRepository    catRepo.CatRepositoryContract
+	matchRepository  matchRepo.MatchRepositoryContract
+	userRepository   userRepo.AuthRepositoryContract
+	contextTimeout   time.Duration
+}
+
+func NewReportService(
+	timeout time.Duration,
+	reportRepository reportRepo.ReportRepositoryContract,
+	catRepository catRepo.CatRepositoryContract,
+	matchRepository matchRepo.MatchRepositoryContract,
+	userRepository userRepo.AuthRepositoryContract,
+) *ReportService {
+	reportService := &ReportService{
+		reportRepository: reportRepository,
+		catRepository:    catRepository,
+		matchRepository:  matchRepository,
+		userRepository:   userRepository,
+		contextTimeout:   timeout,
+	}
+
+	return reportService
+}
+
+func (r ReportService) Create(ctx context.Context, report domain.Report) (domain.Report, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[ReportService.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := r.checkTarget(ctx, report)
+	if err != nil {
+		l.Error("error check report target", zap.Error(err))
+		return report, err
+	}
+
+	report, err = r.reportRepository.Create(ctx, report)
+	if err != nil {
+		l.Error("error create report", zap.Error(err))
+		return report, err
+	}
+
+	return report, nil
+}
+
+// checkTarget makes sure the reported content exists and was not created by the reporter.
+// Match messages can only be reported by the receiver of the match request.
+func (r ReportService) checkTarget(ctx context.Context, report domain.Report) error {
+	callerInfo := "[ReportService.checkTarget]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	switch report.TargetType {
+	case domain.ReportTargetCat:
+		// no owner filter, a user may report cats of users they blocked
+		cats, err := r.catRepository.Get(ctx, ulid.ULID{}, domain.QueryParam{
+			ID: report.TargetID,
+		}, false)
+		if err != nil {
+			l.Error("error get cat", zap.Error(err))
+			return err
+		}
+
+		if len(cats) != 1 {
+			return domain.ErrReportTargetNotFound
+		}
+
+		if cats[0].UserID == report.ReporterID {
+			return domain.ErrReportOwnTarget
+		}
+	case domain.ReportTargetMatch:
+		detailMatch, err := r.matchRepository.Get(ctx, report.TargetID)
+		if err != nil {
+			if errors.Is(err, domain.ErrMatchNotFound) {
+				return domain.ErrReportTargetNotFound
+			}
+			l.Error("error get match", zap.Error(err))
+			return err
+		}
+
+		if detailMatch.Issuer.ID == report.ReporterID {
+			return domain.ErrReportOwnTarget
+		}
+
+		if detailMatch.Receiver.ID != report.ReporterID {
+			return domain.ErrReportTargetNotFound
+		}
+	case domain.ReportTargetUser:
+		if report.TargetID == report.ReporterID {
+			return domain.ErrReportOwnTarget
+		}
+
+		_, err := r.userRepository.Get(ctx, report.TargetID)
+		if err != nil {
+			if errors.Is(err, domain.UserNotFoundError) {
+				return domain.ErrReportTargetNotFound
+			}
+			l.Error("error get user", zap.Error(err))
+			return err
+		}
+	}
+
+	return nil
+}
+
+var _ ReportServiceContract = (*ReportService)(nil)
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"cats-social/internal/domain"
+)
+
+type ReportServiceContract interface {
+	Create(ctx context.Context, report domain.Report) (domain.Report, error)
+}
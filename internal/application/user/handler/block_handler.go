@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+func (h userHandler) BlockUser(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.BlockUser]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	blockedID, err := ulid.Parse(c.Params(blockedIDFromParam))
+	if err != nil {
+		l.Error("error parse user id",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: userNotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": domain.UserNotFoundError.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+	}
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	err = h.userService.BlockUser(userCtx, userData.ID, blockedID)
+	if err != nil {
+		var res baseResponse
+		switch {
+		case errors.Is(err, domain.ErrCannotBlockSelf):
+			l.Error("cannot block self",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: cannotBlockSelfMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		case errors.Is(err, domain.UserNotFoundError):
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error block user",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res := baseResponse{
+		Message: successBlockUserMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusCreated).JSON(res)
+}
+
+func (h userHandler) UnblockUser(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.UnblockUser]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	blockedID, err := ulid.Parse(c.Params(blockedIDFromParam))
+	if err != nil {
+		l.Error("error parse user id",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: blockNotFoundMessage,
+			Data: fiber.Map{
+				"error": domain.ErrBlockNotFound.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+	}
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	err = h.userService.UnblockUser(userCtx, userData.ID, blockedID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBlockNotFound) {
+			l.Error("block not found",
+				zap.Error(err),
+			)
+			res := baseResponse{
+				Message: blockNotFoundMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		}
+		l.Error("error unblock user",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successUnblockUserMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.JSON(res)
+}
+
+func (h userHandler) ListBlocks(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.ListBlocks]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	blocks, err := h.userService.ListBlocks(userCtx, userData.ID)
+	if err != nil {
+		l.Error("error get blocks",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	blocksResponse := make([]blockResponse, len(blocks))
+	for i, block := range blocks {
+		blocksResponse[i] = blockResponse{
+			UserID:    block.Blocked.ID.String(),
+			Name:      block.Blocked.Name,
+			CreatedAt: block.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	res := baseResponse{
+		Message: successGetBlocksMessage,
+		Data:    blocksResponse,
+	}
+
+	return c.JSON(res)
+}
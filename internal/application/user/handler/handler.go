@@ -2,9 +2,13 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/oklog/ulid/v2"
 	"go.uber.org/zap"
 
 	"cats-social/common/logger"
@@ -14,17 +18,42 @@ import (
 
 type authHandler struct {
 	authService service.AuthServiceContract
+	oidcService service.OIDCServiceContract
+	policy      domain.CredentialPolicy
 }
 
-func NewAuthHandler(router fiber.Router, authService service.AuthServiceContract) {
+func NewAuthHandler(
+	router fiber.Router,
+	jwtMiddleware fiber.Handler,
+	authService service.AuthServiceContract,
+	oidcService service.OIDCServiceContract,
+	policy domain.CredentialPolicy,
+) {
 	handler := authHandler{
 		authService: authService,
+		oidcService: oidcService,
+		policy:      policy,
 	}
 
 	authRouter := router.Group("/user")
 
 	authRouter.Post("/register", handler.Register)
 	authRouter.Post("/login", handler.Login)
+	authRouter.Post("/login/2fa", handler.LoginMFA)
+	authRouter.Get("/oidc/login", handler.OIDCLogin)
+	authRouter.Get("/oidc/callback", handler.OIDCCallback)
+	authRouter.Post("/token/refresh", handler.RefreshToken)
+	authRouter.Post("/logout", jwtMiddleware, handler.Logout)
+	authRouter.Post("/logout-all", jwtMiddleware, handler.LogoutAll)
+	authRouter.Post("/password", jwtMiddleware, handler.ChangePassword)
+	authRouter.Post("/password/forgot", handler.ForgotPassword)
+	authRouter.Post("/password/reset", handler.ResetPassword)
+	authRouter.Post("/verify", handler.VerifyEmail)
+	authRouter.Post("/verify/resend", jwtMiddleware, handler.ResendVerification)
+	authRouter.Post("/2fa/setup", jwtMiddleware, handler.SetupMFA)
+	authRouter.Post("/2fa/enable", jwtMiddleware, handler.EnableMFA)
+	authRouter.Get("/sessions", jwtMiddleware, handler.ListSessions)
+	authRouter.Delete("/sessions/:"+sessionIDFromParam, jwtMiddleware, handler.RevokeSession)
 }
 
 func (h authHandler) Register(c *fiber.Ctx) error {
@@ -47,7 +76,7 @@ func (h authHandler) Register(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(res)
 	}
 
-	if err := req.validate(); err != nil {
+	if err := req.validate(h.policy); err != nil {
 		l.Error("error validate data",
 			zap.Error(err),
 		)
@@ -92,7 +121,9 @@ func (h authHandler) Register(c *fiber.Ctx) error {
 		return c.Status(http.StatusInternalServerError).JSON(res)
 	}
 
-	token, err := h.authService.GenerateToken(userCtx, user)
+	client := domain.NewSessionClient(c.Get(fiber.HeaderUserAgent), c.IP())
+
+	token, err := h.authService.GenerateToken(userCtx, user, client)
 	if err != nil {
 		l.Error("error generate token",
 			zap.Error(err),
@@ -109,9 +140,10 @@ func (h authHandler) Register(c *fiber.Ctx) error {
 	res = baseResponse{
 		Message: successRegisterMessage,
 		Data: authResponse{
-			Email:       user.Email,
-			Name:        user.Name,
-			AccessToken: token,
+			Email:        user.Email,
+			Name:         user.Name,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
 		},
 	}
 	return c.Status(http.StatusCreated).JSON(res)
@@ -137,7 +169,7 @@ func (h authHandler) Login(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(res)
 	}
 
-	if err := req.validate(); err != nil {
+	if err := req.validate(h.policy); err != nil {
 		l.Error("error validate data",
 			zap.Error(err),
 		)
@@ -155,34 +187,49 @@ func (h authHandler) Login(c *fiber.Ctx) error {
 		Password: req.Password,
 	}
 
-	user, err := h.authService.Login(userCtx, loginData)
+	user, err := h.authService.Login(userCtx, loginData, c.IP())
 	if err != nil {
+		var lockedErr domain.LoginLockedError
 		switch {
-		case errors.Is(err, domain.UserNotFoundError):
-			l.Error("user not found",
+		case errors.As(err, &lockedErr):
+			l.Error("login locked",
 				zap.Error(err),
 			)
 			res = baseResponse{
-				Message: userNotFoundErrorMessage,
+				Message: tooManyAttemptsMessage,
 				Data: fiber.Map{
 					"error": err.Error(),
 				},
 			}
 
-			return c.Status(http.StatusNotFound).JSON(res)
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			return c.Status(http.StatusTooManyRequests).JSON(res)
 
-		case errors.Is(err, domain.InvalidPassword):
-			l.Error("invalid password",
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			l.Error("invalid credentials",
 				zap.Error(err),
 			)
 			res = baseResponse{
-				Message: invalidPasswordMessage,
+				Message: invalidCredentialsMessage,
 				Data: fiber.Map{
 					"error": err.Error(),
 				},
 			}
 
-			return c.Status(http.StatusBadRequest).JSON(res)
+			return c.Status(http.StatusUnauthorized).JSON(res)
+
+		case errors.Is(err, domain.ErrUserSuspended):
+			l.Error("user suspended",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userSuspendedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusForbidden).JSON(res)
 
 		default:
 			l.Error("error login user",
@@ -199,9 +246,131 @@ func (h authHandler) Login(c *fiber.Ctx) error {
 		}
 	}
 
-	token, err := h.authService.GenerateToken(userCtx, user)
+	if user.MFAEnabled {
+		return h.challengeMFA(c, user)
+	}
+
+	return h.issueTokens(c, user)
+}
+
+func (h authHandler) RefreshToken(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.RefreshToken]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &refreshTokenRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	user, token, err := h.authService.RefreshToken(userCtx, req.RefreshToken)
 	if err != nil {
-		l.Error("error generate token",
+		switch {
+		case errors.Is(err, domain.ErrInvalidRefreshToken), errors.Is(err, domain.ErrRefreshTokenReused):
+			l.Error("invalid refresh token",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidRefreshTokenMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusUnauthorized).JSON(res)
+
+		case errors.Is(err, domain.ErrUserSuspended):
+			l.Error("user suspended",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userSuspendedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusForbidden).JSON(res)
+
+		default:
+			l.Error("error refresh token",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res = baseResponse{
+		Message: successRefreshTokenMessage,
+		Data: authResponse{
+			Email:        user.Email,
+			Name:         user.Name,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+		},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) Logout(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.Logout]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	tokenData := c.Locals(domain.AccessTokenFromToken).(domain.AccessToken)
+
+	// the refresh token is optional, without it only the access token is revoked
+	req, res := &logoutRequest{}, baseResponse{}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			l.Error("error binding data",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InvalidRequestBodyMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		}
+	}
+
+	err := h.authService.Logout(userCtx, tokenData, req.RefreshToken)
+	if err != nil {
+		l.Error("error logout user",
 			zap.Error(err),
 		)
 		res = baseResponse{
@@ -214,13 +383,822 @@ func (h authHandler) Login(c *fiber.Ctx) error {
 	}
 
 	res = baseResponse{
-		Message: successLoginMessage,
+		Message: successLogoutMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) LogoutAll(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.LogoutAll]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	err := h.authService.LogoutAll(userCtx, userData.ID)
+	if err != nil {
+		l.Error("error logout user from all devices",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successLogoutAllMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) ChangePassword(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.ChangePassword]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	req, res := &changePasswordRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(h.policy); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	client := domain.NewSessionClient(c.Get(fiber.HeaderUserAgent), c.IP())
+
+	user, token, err := h.authService.ChangePassword(userCtx, userData.ID, req.OldPassword, req.NewPassword, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPasswordNotSet):
+			l.Error("user has no password",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: passwordNotSetMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusConflict).JSON(res)
+
+		case errors.Is(err, domain.InvalidPassword):
+			l.Error("invalid password",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidPasswordMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusBadRequest).JSON(res)
+
+		case errors.Is(err, domain.UserNotFoundError):
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusNotFound).JSON(res)
+
+		default:
+			l.Error("error change password",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res = baseResponse{
+		Message: successChangePasswordMessage,
 		Data: authResponse{
-			Email:       user.Email,
-			Name:        user.Name,
-			AccessToken: token,
+			Email:        user.Email,
+			Name:         user.Name,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
 		},
 	}
 
 	return c.Status(http.StatusOK).JSON(res)
 }
+
+func (h authHandler) ForgotPassword(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.ForgotPassword]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &forgotPasswordRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := h.authService.ForgotPassword(userCtx, req.Email); err != nil {
+		l.Error("error forgot password",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	// same response whether the email is registered or not
+	res = baseResponse{
+		Message: successForgotPasswordMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) ResetPassword(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.ResetPassword]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &resetPasswordRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(h.policy); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	err := h.authService.ResetPassword(userCtx, req.Token, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidResetToken) {
+			l.Error("invalid reset token",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidResetTokenMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		}
+		l.Error("error reset password",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successResetPasswordMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) VerifyEmail(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.VerifyEmail]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &verifyEmailRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	err := h.authService.VerifyEmail(userCtx, req.Token)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVerificationToken) {
+			l.Error("invalid verification token",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidVerificationMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		}
+		l.Error("error verify email",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successVerifyEmailMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) ResendVerification(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.ResendVerification]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	err := h.authService.ResendVerification(userCtx, userData.ID)
+	if err != nil {
+		var res baseResponse
+		switch {
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			l.Error("email already verified",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: alreadyVerifiedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		case errors.Is(err, domain.UserNotFoundError):
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error resend verification email",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res := baseResponse{
+		Message: successResendVerifyMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) LoginMFA(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.LoginMFA]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &loginMFARequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	user, err := h.authService.LoginMFA(userCtx, req.MFAToken, req.Code, c.IP())
+	if err != nil {
+		var lockedErr domain.LoginLockedError
+		switch {
+		case errors.As(err, &lockedErr):
+			l.Error("login locked",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: tooManyAttemptsMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+			return c.Status(http.StatusTooManyRequests).JSON(res)
+
+		case errors.Is(err, domain.ErrInvalidMFAToken):
+			l.Error("invalid mfa token",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidMFATokenMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusUnauthorized).JSON(res)
+
+		case errors.Is(err, domain.ErrInvalidMFACode):
+			l.Error("invalid mfa code",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidMFACodeMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusUnauthorized).JSON(res)
+
+		default:
+			l.Error("error login user",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	return h.issueTokens(c, user)
+}
+
+func (h authHandler) SetupMFA(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.SetupMFA]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	setup, err := h.authService.SetupMFA(userCtx, userData.ID)
+	if err != nil {
+		var res baseResponse
+		switch {
+		case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+			l.Error("mfa already enabled",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: mfaAlreadyEnabledMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		case errors.Is(err, domain.UserNotFoundError):
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error setup mfa",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res := baseResponse{
+		Message: successSetupMFAMessage,
+		Data: mfaSetupResponse{
+			Secret:     setup.Secret,
+			OTPAuthURI: setup.URI,
+		},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) EnableMFA(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.EnableMFA]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &enableMFARequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	recoveryCodes, err := h.authService.EnableMFA(userCtx, userData.ID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+			l.Error("mfa already enabled",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: mfaAlreadyEnabledMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		case errors.Is(err, domain.ErrMFANotSetUp):
+			l.Error("mfa not set up",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: mfaNotSetUpMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		case errors.Is(err, domain.ErrInvalidMFACode):
+			l.Error("invalid mfa code",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidMFACodeMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		default:
+			l.Error("error enable mfa",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res = baseResponse{
+		Message: successEnableMFAMessage,
+		Data: mfaEnableResponse{
+			RecoveryCodes: recoveryCodes,
+		},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+// challengeMFA answers a successful first login step of a user with two-factor authentication.
+func (h authHandler) challengeMFA(c *fiber.Ctx, user domain.User) error {
+	callerInfo := "[authHandler.challengeMFA]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	mfaToken, err := h.authService.IssueMFAChallenge(userCtx, user)
+	if err != nil {
+		l.Error("error issue mfa challenge",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: mfaRequiredMessage,
+		Data: mfaChallengeResponse{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+// issueTokens answers a completed login with a new access and refresh token.
+func (h authHandler) issueTokens(c *fiber.Ctx, user domain.User) error {
+	callerInfo := "[authHandler.issueTokens]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	client := domain.NewSessionClient(c.Get(fiber.HeaderUserAgent), c.IP())
+
+	token, err := h.authService.GenerateToken(userCtx, user, client)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserSuspended) {
+			l.Error("user suspended",
+				zap.Error(err),
+			)
+			res := baseResponse{
+				Message: userSuspendedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusForbidden).JSON(res)
+		}
+		l.Error("error generate token",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successLoginMessage,
+		Data: authResponse{
+			Email:        user.Email,
+			Name:         user.Name,
+			AccessToken:  token.AccessToken,
+			RefreshToken: token.RefreshToken,
+		},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) ListSessions(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.ListSessions]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+	tokenData := c.Locals(domain.AccessTokenFromToken).(domain.AccessToken)
+
+	sessions, err := h.authService.ListSessions(userCtx, userData.ID)
+	if err != nil {
+		l.Error("error get sessions",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	sessionsResponse := make([]sessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionsResponse[i] = sessionResponse{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    session.ID == tokenData.SessionID,
+			CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: session.LastSeenAt.Format(time.RFC3339),
+		}
+	}
+
+	res := baseResponse{
+		Message: successGetSessionsMessage,
+		Data:    sessionsResponse,
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
+
+func (h authHandler) RevokeSession(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.RevokeSession]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	sessionID, err := ulid.Parse(c.Params(sessionIDFromParam))
+	if err != nil {
+		l.Error("error parse session id",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: sessionNotFoundMessage,
+			Data: fiber.Map{
+				"error": domain.ErrSessionNotFound.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+	}
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	err = h.authService.RevokeSession(userCtx, userData.ID, sessionID)
+	if err != nil {
+		var res baseResponse
+		switch {
+		case errors.Is(err, domain.ErrSessionNotFound):
+			l.Error("session not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: sessionNotFoundMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error revoke session",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res := baseResponse{
+		Message: successRevokeSessionMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.Status(http.StatusOK).JSON(res)
+}
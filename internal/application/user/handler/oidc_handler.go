@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+func (h authHandler) OIDCLogin(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.OIDCLogin]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	authURL, err := h.oidcService.AuthorizationURL(userCtx)
+	if err != nil {
+		var res baseResponse
+		switch {
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			l.Error("openid connect disabled",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: oidcNotConfiguredMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error start oidc login",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	return c.Redirect(authURL, http.StatusFound)
+}
+
+func (h authHandler) OIDCCallback(c *fiber.Ctx) error {
+	callerInfo := "[authHandler.OIDCCallback]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	req, res := &oidcCallbackRequest{}, baseResponse{}
+	if err := c.QueryParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	// the identity provider redirects back with an error when the user denied the login
+	if req.Error != "" {
+		l.Error("identity provider returned an error",
+			zap.String("error", req.Error),
+			zap.String("description", req.ErrorDescription),
+		)
+		res = baseResponse{
+			Message: oidcLoginFailedMessage,
+			Data: fiber.Map{
+				"error": req.Error,
+			},
+		}
+		return c.Status(http.StatusUnauthorized).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	user, err := h.oidcService.Callback(userCtx, req.Code, req.State)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrOIDCNotConfigured):
+			l.Error("openid connect disabled",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: oidcNotConfiguredMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		case errors.Is(err, domain.ErrInvalidOIDCState):
+			l.Error("invalid login state",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidOIDCStateMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		case errors.Is(err, domain.ErrOIDCLoginFailed):
+			l.Error("oidc login failed",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: oidcLoginFailedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusUnauthorized).JSON(res)
+		case errors.Is(err, domain.ErrOIDCEmailNotVerified):
+			l.Error("email not verified",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: oidcEmailNotVerifiedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusForbidden).JSON(res)
+		case errors.Is(err, domain.ErrOIDCAccountNotVerified):
+			l.Error("account not verified",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: oidcAccountNotVerifiedMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		case errors.Is(err, domain.DuplicateEmailError):
+			l.Error("duplicate email",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: duplicateEmailErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		default:
+			l.Error("error oidc callback",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	// the identity provider replaces the password, not the second factor
+	if user.MFAEnabled {
+		return h.challengeMFA(c, user)
+	}
+
+	return h.issueTokens(c, user)
+}
@@ -5,15 +5,63 @@ import (
 
 	"github.com/asaskevich/govalidator"
 	"go.uber.org/multierr"
+
+	"cats-social/internal/domain"
 )
 
 const (
-	duplicateEmailErrorMessage = "Email already exists"
-	userNotFoundErrorMessage   = "User not found"
-	invalidPasswordMessage     = "Invalid password"
+	sessionIDFromParam = "id"
+	ownerIDFromParam   = "id"
+	blockedIDFromParam = "userId"
+)
 
-	successRegisterMessage = "User registered successfully"
-	successLoginMessage    = "User logged in successfully"
+const (
+	duplicateEmailErrorMessage    = "Email already exists"
+	userNotFoundErrorMessage      = "User not found"
+	invalidPasswordMessage        = "Invalid password"
+	passwordNotSetMessage         = "Set a password through the password reset first"
+	invalidRefreshTokenMessage    = "Invalid refresh token"
+	invalidResetTokenMessage      = "Invalid password reset token"
+	invalidVerificationMessage    = "Invalid verification token"
+	alreadyVerifiedMessage        = "Email already verified"
+	invalidCredentialsMessage     = "Invalid email or password"
+	tooManyAttemptsMessage        = "Too many failed login attempts, try again later"
+	invalidMFATokenMessage        = "Invalid or expired two-factor authentication token"
+	invalidMFACodeMessage         = "Invalid two-factor authentication code"
+	mfaNotSetUpMessage            = "Two-factor authentication is not set up"
+	mfaAlreadyEnabledMessage      = "Two-factor authentication already enabled"
+	oidcNotConfiguredMessage      = "Single sign-on is not available"
+	invalidOIDCStateMessage       = "Invalid or expired single sign-on state, start the login again"
+	oidcLoginFailedMessage        = "Single sign-on failed"
+	oidcEmailNotVerifiedMessage   = "The identity provider did not confirm your email address"
+	oidcAccountNotVerifiedMessage = "An unverified account already uses this email address"
+	sessionNotFoundMessage        = "Session not found"
+	cannotBlockSelfMessage        = "You can't block yourself"
+	blockNotFoundMessage          = "User is not blocked"
+	userSuspendedMessage          = "Your account has been suspended"
+
+	successRegisterMessage        = "User registered successfully"
+	successLoginMessage           = "User logged in successfully"
+	successRefreshTokenMessage    = "Token refreshed successfully"
+	successLogoutMessage          = "User logged out successfully"
+	successLogoutAllMessage       = "User logged out from all devices successfully"
+	successGetProfileMessage      = "Success"
+	successUpdateProfileMessage   = "Profile updated successfully"
+	successChangePasswordMessage  = "Password changed successfully"
+	successForgotPasswordMessage  = "If the email is registered, a password reset token has been sent"
+	successResetPasswordMessage   = "Password reset successfully"
+	successVerifyEmailMessage     = "Email verified successfully, refresh your token to use it"
+	successResendVerifyMessage    = "Verification email sent"
+	successDeleteAccountMessage   = "Account deleted successfully"
+	mfaRequiredMessage            = "Two-factor authentication required"
+	successSetupMFAMessage        = "Scan the URI with an authenticator app and confirm it with a code"
+	successEnableMFAMessage       = "Two-factor authentication enabled, store the recovery codes safely"
+	successGetSessionsMessage     = "Success"
+	successRevokeSessionMessage   = "Session revoked successfully"
+	successGetOwnerProfileMessage = "Success"
+	successBlockUserMessage       = "User blocked successfully"
+	successUnblockUserMessage     = "User unblocked successfully"
+	successGetBlocksMessage       = "Success"
 )
 
 type baseResponse struct {
@@ -27,7 +75,7 @@ type registerRequest struct {
 	Password string `json:"password"`
 }
 
-func (r registerRequest) validate() error {
+func (r registerRequest) validate(policy domain.CredentialPolicy) error {
 	var errs error
 
 	if r.Email != "" && !govalidator.IsEmail(r.Email) {
@@ -37,15 +85,15 @@ func (r registerRequest) validate() error {
 		errs = multierr.Append(errs, errors.New("email is required"))
 	}
 
-	if r.Name != "" && len(r.Name) < 5 || len(r.Name) > 50 {
-		errs = multierr.Append(errs, errors.New("name must be between 5 and 50 characters"))
+	if r.Name != "" {
+		errs = multierr.Append(errs, policy.ValidateName("name", r.Name))
 	}
 	if r.Name == "" {
 		errs = multierr.Append(errs, errors.New("name is required"))
 	}
 
-	if r.Password != "" && len(r.Password) < 5 || len(r.Password) > 15 {
-		errs = multierr.Append(errs, errors.New("password must be between 5 and 15 characters"))
+	if r.Password != "" {
+		errs = multierr.Append(errs, policy.ValidatePassword("password", r.Password))
 	}
 	if r.Password == "" {
 		errs = multierr.Append(errs, errors.New("password is required"))
@@ -59,9 +107,10 @@ func (r registerRequest) validate() error {
 }
 
 type authResponse struct {
-	Email       string `json:"email"`
-	Name        string `json:"name"`
-	AccessToken string `json:"accessToken"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
 }
 
 type loginRequest struct {
@@ -69,7 +118,7 @@ type loginRequest struct {
 	Password string `json:"password"`
 }
 
-func (r loginRequest) validate() error {
+func (r loginRequest) validate(policy domain.CredentialPolicy) error {
 	var errs error
 
 	if r.Email != "" && !govalidator.IsEmail(r.Email) {
@@ -79,8 +128,8 @@ func (r loginRequest) validate() error {
 		errs = multierr.Append(errs, errors.New("email is required"))
 	}
 
-	if r.Password != "" && len(r.Password) < 5 || len(r.Password) > 15 {
-		errs = multierr.Append(errs, errors.New("password must be between 5 and 15 characters"))
+	if r.Password != "" {
+		errs = multierr.Append(errs, policy.ValidateLoginPassword("password", r.Password))
 	}
 	if r.Password == "" {
 		errs = multierr.Append(errs, errors.New("password is required"))
@@ -92,3 +141,242 @@ func (r loginRequest) validate() error {
 
 	return nil
 }
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (r refreshTokenRequest) validate() error {
+	if r.RefreshToken == "" {
+		return errors.New("refreshToken is required")
+	}
+
+	return nil
+}
+
+type logoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type updateProfileRequest struct {
+	Name string `json:"name"`
+}
+
+func (r updateProfileRequest) validate(policy domain.CredentialPolicy) error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	return policy.ValidateName("name", r.Name)
+}
+
+type profileResponse struct {
+	ID         string `json:"id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	Verified   bool   `json:"verified"`
+	CatCount   int    `json:"catCount"`
+	MatchCount int    `json:"matchCount"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type ownerProfileResponse struct {
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Email       string             `json:"email,omitempty"`
+	MemberSince string             `json:"memberSince"`
+	Cats        []ownerCatResponse `json:"cats"`
+}
+
+type ownerCatResponse struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	Race        domain.CatRace `json:"race"`
+	Sex         domain.CatSex  `json:"sex"`
+	AgeInMonth  int            `json:"ageInMonth"`
+	Description string         `json:"description"`
+	ImageUrls   []string       `json:"imageUrls"`
+	HasMatched  bool           `json:"hasMatched"`
+	CreatedAt   string         `json:"createdAt"`
+}
+
+type changePasswordRequest struct {
+	OldPassword string `json:"oldPassword"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (r changePasswordRequest) validate(policy domain.CredentialPolicy) error {
+	var errs error
+
+	if r.OldPassword == "" {
+		errs = multierr.Append(errs, errors.New("oldPassword is required"))
+	}
+
+	if r.NewPassword != "" {
+		errs = multierr.Append(errs, policy.ValidatePassword("newPassword", r.NewPassword))
+	}
+	if r.NewPassword == "" {
+		errs = multierr.Append(errs, errors.New("newPassword is required"))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+func (r forgotPasswordRequest) validate() error {
+	if r.Email == "" {
+		return errors.New("email is required")
+	}
+
+	if !govalidator.IsEmail(r.Email) {
+		return errors.New("invalid email format")
+	}
+
+	return nil
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
+
+func (r resetPasswordRequest) validate(policy domain.CredentialPolicy) error {
+	var errs error
+
+	if r.Token == "" {
+		errs = multierr.Append(errs, errors.New("token is required"))
+	}
+
+	if r.NewPassword != "" {
+		errs = multierr.Append(errs, policy.ValidatePassword("newPassword", r.NewPassword))
+	}
+	if r.NewPassword == "" {
+		errs = multierr.Append(errs, errors.New("newPassword is required"))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+func (r verifyEmailRequest) validate() error {
+	if r.Token == "" {
+		return errors.New("token is required")
+	}
+
+	return nil
+}
+
+type deleteAccountRequest struct {
+	Password string `json:"password"`
+}
+
+func (r deleteAccountRequest) validate() error {
+	if r.Password == "" {
+		return errors.New("password is required")
+	}
+
+	return nil
+}
+
+type mfaChallengeResponse struct {
+	MFARequired bool   `json:"mfaRequired"`
+	MFAToken    string `json:"mfaToken"`
+}
+
+type loginMFARequest struct {
+	MFAToken string `json:"mfaToken"`
+	Code     string `json:"code"`
+}
+
+func (r loginMFARequest) validate() error {
+	var errs error
+
+	if r.MFAToken == "" {
+		errs = multierr.Append(errs, errors.New("mfaToken is required"))
+	}
+
+	if r.Code == "" {
+		errs = multierr.Append(errs, errors.New("code is required"))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+type mfaSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauthUri"`
+}
+
+type enableMFARequest struct {
+	Code string `json:"code"`
+}
+
+func (r enableMFARequest) validate() error {
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+
+	return nil
+}
+
+type mfaEnableResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+type oidcCallbackRequest struct {
+	Code             string `query:"code"`
+	State            string `query:"state"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+}
+
+func (r oidcCallbackRequest) validate() error {
+	var errs error
+
+	if r.Code == "" {
+		errs = multierr.Append(errs, errors.New("code is required"))
+	}
+
+	if r.State == "" {
+		errs = multierr.Append(errs, errors.New("state is required"))
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	return nil
+}
+
+type sessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"userAgent"`
+	IPAddress  string `json:"ipAddress"`
+	Current    bool   `json:"current"`
+	CreatedAt  string `json:"createdAt"`
+	LastSeenAt string `json:"lastSeenAt"`
+}
+
+type blockResponse struct {
+	UserID    string `json:"userId"`
+	Name      string `json:"name"`
+	CreatedAt string `json:"createdAt"`
+}
@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/application/user/service"
+	"cats-social/internal/domain"
+)
+
+type userHandler struct {
+	userService service.UserServiceContract
+	policy      domain.CredentialPolicy
+}
+
+func NewUserHandler(
+	router fiber.Router,
+	jwtMiddleware fiber.Handler,
+	userService service.UserServiceContract,
+	policy domain.CredentialPolicy,
+) {
+	handler := userHandler{
+		userService: userService,
+		policy:      policy,
+	}
+
+	userRouter := router.Group("/user")
+
+	userRouter.Get("/me", jwtMiddleware, handler.GetProfile)
+	userRouter.Patch("/me", jwtMiddleware, handler.UpdateProfile)
+	userRouter.Delete("/me", jwtMiddleware, handler.DeleteAccount)
+	userRouter.Get("/:"+ownerIDFromParam+"/profile", jwtMiddleware, handler.GetOwnerProfile)
+	userRouter.Post("/block/:"+blockedIDFromParam, jwtMiddleware, handler.BlockUser)
+	userRouter.Delete("/block/:"+blockedIDFromParam, jwtMiddleware, handler.UnblockUser)
+	userRouter.Get("/blocks", jwtMiddleware, handler.ListBlocks)
+}
+
+func (h userHandler) GetProfile(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.GetProfile]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	profile, err := h.userService.GetProfile(userCtx, userData.ID)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res := baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		}
+		l.Error("error get profile",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successGetProfileMessage,
+		Data:    newProfileResponse(profile),
+	}
+
+	return c.JSON(res)
+}
+
+func (h userHandler) GetOwnerProfile(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.GetOwnerProfile]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	ownerID, err := ulid.Parse(c.Params(ownerIDFromParam))
+	if err != nil {
+		l.Error("error parse user id",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: userNotFoundErrorMessage,
+			Data: fiber.Map{
+				"error": domain.UserNotFoundError.Error(),
+			},
+		}
+		return c.Status(http.StatusNotFound).JSON(res)
+	}
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	profile, err := h.userService.GetOwnerProfile(userCtx, userData.ID, ownerID)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res := baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		}
+		l.Error("error get owner profile",
+			zap.Error(err),
+		)
+		res := baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res := baseResponse{
+		Message: successGetOwnerProfileMessage,
+		Data:    newOwnerProfileResponse(profile),
+	}
+
+	return c.JSON(res)
+}
+
+func (h userHandler) UpdateProfile(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.UpdateProfile]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	req, res := &updateProfileRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(h.policy); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	updateData := domain.User{
+		ID:   userData.ID,
+		Name: req.Name,
+	}
+
+	profile, err := h.userService.UpdateProfile(userCtx, updateData)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		}
+		l.Error("error update profile",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InternalServerErrorMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusInternalServerError).JSON(res)
+	}
+
+	res = baseResponse{
+		Message: successUpdateProfileMessage,
+		Data:    newProfileResponse(profile),
+	}
+
+	return c.JSON(res)
+}
+
+func (h userHandler) DeleteAccount(c *fiber.Ctx) error {
+	callerInfo := "[userHandler.DeleteAccount]"
+
+	userCtx := c.UserContext()
+	l := logger.FromCtx(userCtx).With(zap.String("caller", callerInfo))
+
+	userData := c.Locals(domain.UserFromToken).(domain.User)
+
+	req, res := &deleteAccountRequest{}, baseResponse{}
+	if err := c.BodyParser(req); err != nil {
+		l.Error("error binding data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	if err := req.validate(); err != nil {
+		l.Error("error validate data",
+			zap.Error(err),
+		)
+		res = baseResponse{
+			Message: domain.InvalidRequestBodyMessage,
+			Data: fiber.Map{
+				"error": err.Error(),
+			},
+		}
+		return c.Status(http.StatusBadRequest).JSON(res)
+	}
+
+	err := h.userService.DeleteAccount(userCtx, userData.ID, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrPasswordNotSet):
+			l.Error("user has no password",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: passwordNotSetMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusConflict).JSON(res)
+		case errors.Is(err, domain.InvalidPassword):
+			l.Error("invalid password",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: invalidPasswordMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusBadRequest).JSON(res)
+		case errors.Is(err, domain.UserNotFoundError):
+			l.Error("user not found",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: userNotFoundErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusNotFound).JSON(res)
+		default:
+			l.Error("error delete account",
+				zap.Error(err),
+			)
+			res = baseResponse{
+				Message: domain.InternalServerErrorMessage,
+				Data: fiber.Map{
+					"error": err.Error(),
+				},
+			}
+			return c.Status(http.StatusInternalServerError).JSON(res)
+		}
+	}
+
+	res = baseResponse{
+		Message: successDeleteAccountMessage,
+		Data:    fiber.Map{},
+	}
+
+	return c.JSON(res)
+}
+
+func newProfileResponse(profile domain.UserProfile) profileResponse {
+	return profileResponse{
+		ID:         profile.ID.String(),
+		Email:      profile.Email,
+		Name:       profile.Name,
+		Verified:   profile.Verified,
+		CatCount:   profile.CatCount,
+		MatchCount: profile.MatchCount,
+		CreatedAt:  profile.CreatedAt.Format(time.DateOnly),
+	}
+}
+
+func newOwnerProfileResponse(profile domain.OwnerProfile) ownerProfileResponse {
+	cats := make([]ownerCatResponse, len(profile.Cats))
+	for i, cat := range profile.Cats {
+		cats[i] = ownerCatResponse{
+			ID:          cat.ID.String(),
+			Name:        cat.Name,
+			Race:        cat.Race,
+			Sex:         cat.Sex,
+			AgeInMonth:  cat.AgeInMonth,
+			Description: cat.Description,
+			ImageUrls:   cat.ImageUrls,
+			HasMatched:  cat.HasMatched,
+			CreatedAt:   cat.CreatedAt.Format(time.DateOnly),
+		}
+	}
+
+	res := ownerProfileResponse{
+		ID:          profile.ID.String(),
+		Name:        profile.Name,
+		MemberSince: profile.CreatedAt.Format(time.DateOnly),
+		Cats:        cats,
+	}
+	if profile.EmailVisible {
+		res.Email = profile.Email
+	}
+
+	return res
+}
@@ -1,21 +1,96 @@
 package user
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"cats-social/common/configs"
+	"cats-social/common/mailer"
+	"cats-social/common/oidc"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
 	"cats-social/internal/application/user/handler"
 	"cats-social/internal/application/user/repository"
 	"cats-social/internal/application/user/service"
+	"cats-social/internal/domain"
 )
 
-func NewModule(router fiber.Router, db *pgxpool.Pool) {
+func NewModule(
+	router fiber.Router,
+	db *pgxpool.Pool,
+	revocationRepository repository.RevocationRepositoryContract,
+	sessionRepository repository.SessionRepositoryContract,
+	jwtMiddleware fiber.Handler,
+) {
 	ctxTimeout := time.Duration(configs.Runtime.App.ContextTimeout) * time.Second
 
 	authRepository := repository.NewAuthRepository(db)
-	authService := service.NewAuthService(ctxTimeout, authRepository)
-	handler.NewAuthHandler(router, authService)
+	tokenRepository := repository.NewTokenRepository(db)
+	passwordResetRepository := repository.NewPasswordResetRepository(db)
+	loginAttemptRepository := repository.NewLoginAttemptRepository(db)
+	mfaRepository := repository.NewMFARepository(db)
+	loginThrottle := domain.LoginThrottle{
+		MaxAttempts: configs.Runtime.API.LoginThrottle.MaxAttempts,
+		Window:      time.Duration(configs.Runtime.API.LoginThrottle.Window) * time.Second,
+		BaseLockout: time.Duration(configs.Runtime.API.LoginThrottle.BaseLockout) * time.Second,
+		MaxLockout:  time.Duration(configs.Runtime.API.LoginThrottle.MaxLockout) * time.Second,
+	}
+	credentialPolicy := domain.CredentialPolicy{
+		PasswordMinLength:     configs.Runtime.API.Policy.PasswordMinLength,
+		PasswordMaxLength:     configs.Runtime.API.Policy.PasswordMaxLength,
+		RequireUpper:          configs.Runtime.API.Policy.RequireUpper,
+		RequireLower:          configs.Runtime.API.Policy.RequireLower,
+		RequireDigit:          configs.Runtime.API.Policy.RequireDigit,
+		RequireSymbol:         configs.Runtime.API.Policy.RequireSymbol,
+		RejectCommonPasswords: configs.Runtime.API.Policy.RejectCommonPasswords,
+		NameMinLength:         configs.Runtime.API.Policy.NameMinLength,
+		NameMaxLength:         configs.Runtime.API.Policy.NameMaxLength,
+	}
+	fileMailer := mailer.NewFileMailer(configs.Runtime.Mail.Dir, configs.Runtime.Mail.From)
+	authService := service.NewAuthService(
+		ctxTimeout,
+		authRepository,
+		tokenRepository,
+		revocationRepository,
+		sessionRepository,
+		passwordResetRepository,
+		loginAttemptRepository,
+		mfaRepository,
+		loginThrottle,
+		fileMailer,
+	)
+
+	oidcRepository := repository.NewOIDCRepository(db)
+	oidcProvider := oidc.NewProvider(oidc.Config{
+		Issuer:       configs.Runtime.API.OIDC.Issuer,
+		ClientID:     configs.Runtime.API.OIDC.ClientID,
+		ClientSecret: configs.Runtime.API.OIDC.ClientSecret,
+		RedirectURL:  configs.Runtime.API.OIDC.RedirectURL,
+		Scopes:       configs.Runtime.API.OIDC.Scopes,
+	}, &http.Client{Timeout: ctxTimeout})
+	oidcService := service.NewOIDCService(
+		ctxTimeout,
+		authRepository,
+		oidcRepository,
+		oidcProvider,
+		time.Duration(configs.Runtime.API.OIDC.StateExpire)*time.Second,
+	)
+	handler.NewAuthHandler(router, jwtMiddleware, authService, oidcService, credentialPolicy)
+
+	catRepository := catRepo.NewCatRepository(db)
+	matchRepository := matchRepo.NewMatchRepository(db)
+	blockRepository := repository.NewBlockRepository(db)
+	userService := service.NewUserService(
+		ctxTimeout,
+		authRepository,
+		tokenRepository,
+		revocationRepository,
+		blockRepository,
+		catRepository,
+		matchRepository,
+	)
+	handler.NewUserHandler(router, jwtMiddleware, userService, credentialPolicy)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -17,6 +18,12 @@ import (
 	"cats-social/internal/domain"
 )
 
+// rolesColumn selects the roles of the user in the current row as a text array.
+const rolesColumn = `ARRAY(SELECT role FROM user_roles WHERE user_roles.user_id = users.id ORDER BY role)`
+
+// mfaEnabledColumn selects whether the user in the current row confirmed two-factor authentication.
+const mfaEnabledColumn = `EXISTS(SELECT 1 FROM user_mfa WHERE user_mfa.user_id = users.id AND user_mfa.enabled_at IS NOT NULL)`
+
 type AuthRepository struct {
 	db *pgxpool.Pool
 }
@@ -32,10 +39,14 @@ func (a AuthRepository) Create(ctx context.Context, dUser domain.User) (domain.U
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
 	mUser := user{
-		ID:        id.New(),
-		Email:     dUser.Email,
-		Name:      dUser.Name,
-		Password:  dUser.Password,
+		ID:       id.New(),
+		Email:    dUser.Email,
+		Name:     dUser.Name,
+		Password: dUser.Password,
+		VerifiedAt: sql.NullTime{
+			Time:  time.Now(),
+			Valid: dUser.Verified,
+		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		DeletedAt: sql.NullTime{
@@ -52,7 +63,7 @@ func (a AuthRepository) Create(ctx context.Context, dUser domain.User) (domain.U
 		_ = tx.Rollback(ctx)
 	}()
 
-	insertQuery := `INSERT INTO users (id, email, name, password, created_at, updated_at, deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	insertQuery := `INSERT INTO users (id, email, name, password, verified_at, created_at, updated_at, deleted_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 	_, err = tx.Exec(
 		ctx,
 		insertQuery,
@@ -60,6 +71,7 @@ func (a AuthRepository) Create(ctx context.Context, dUser domain.User) (domain.U
 		mUser.Email,
 		mUser.Name,
 		mUser.Password,
+		mUser.VerifiedAt,
 		mUser.CreatedAt,
 		mUser.UpdatedAt,
 		mUser.DeletedAt,
@@ -81,6 +93,7 @@ func (a AuthRepository) Create(ctx context.Context, dUser domain.User) (domain.U
 	}
 
 	dUser.ID = mUser.ID
+	dUser.CreatedAt = mUser.CreatedAt
 	return dUser, nil
 }
 
@@ -89,8 +102,10 @@ func (a AuthRepository) GetByEmail(ctx context.Context, email string) (domain.Us
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
 	var mUser user
-	query := `SELECT id, email, name, password FROM users WHERE email = $1`
-	err := a.db.QueryRow(ctx, query, email).Scan(&mUser.ID, &mUser.Email, &mUser.Name, &mUser.Password)
+	query := `SELECT id, email, name, password, verified_at, ` + rolesColumn + `, ` + mfaEnabledColumn + `, suspended_at
+		FROM users WHERE lower(email) = $1 AND deleted_at IS NULL`
+	err := a.db.QueryRow(ctx, query, email).
+		Scan(&mUser.ID, &mUser.Email, &mUser.Name, &mUser.Password, &mUser.VerifiedAt, &mUser.Roles, &mUser.MFAEnabled, &mUser.SuspendedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			l.Error("user not found", zap.Error(err))
@@ -101,10 +116,14 @@ func (a AuthRepository) GetByEmail(ctx context.Context, email string) (domain.Us
 	}
 
 	dUser := domain.User{
-		ID:       mUser.ID,
-		Email:    mUser.Email,
-		Name:     mUser.Name,
-		Password: mUser.Password,
+		ID:         mUser.ID,
+		Email:      mUser.Email,
+		Name:       mUser.Name,
+		Password:   mUser.Password,
+		Verified:   mUser.VerifiedAt.Valid,
+		Roles:      toRoles(mUser.Roles),
+		MFAEnabled: mUser.MFAEnabled,
+		Suspended:  mUser.SuspendedAt.Valid,
 	}
 
 	return dUser, nil
@@ -115,8 +134,10 @@ func (a AuthRepository) Get(ctx context.Context, userID ulid.ULID) (domain.User,
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
 	var mUser user
-	query := `SELECT email, name, created_at FROM users WHERE id = $1`
-	err := a.db.QueryRow(ctx, query, userID).Scan(&mUser.Email, &mUser.Name, &mUser.CreatedAt)
+	query := `SELECT email, name, password, verified_at, ` + rolesColumn + `, ` + mfaEnabledColumn + `, suspended_at, created_at
+		FROM users WHERE id = $1 AND deleted_at IS NULL`
+	err := a.db.QueryRow(ctx, query, userID).
+		Scan(&mUser.Email, &mUser.Name, &mUser.Password, &mUser.VerifiedAt, &mUser.Roles, &mUser.MFAEnabled, &mUser.SuspendedAt, &mUser.CreatedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			l.Error("user not found", zap.Error(err))
@@ -127,13 +148,310 @@ func (a AuthRepository) Get(ctx context.Context, userID ulid.ULID) (domain.User,
 	}
 
 	dUser := domain.User{
-		ID:        userID,
-		Name:      mUser.Name,
-		Email:     mUser.Email,
-		CreatedAt: mUser.CreatedAt,
+		ID:         userID,
+		Name:       mUser.Name,
+		Email:      mUser.Email,
+		Password:   mUser.Password,
+		Verified:   mUser.VerifiedAt.Valid,
+		Roles:      toRoles(mUser.Roles),
+		MFAEnabled: mUser.MFAEnabled,
+		Suspended:  mUser.SuspendedAt.Valid,
+		CreatedAt:  mUser.CreatedAt,
+	}
+
+	return dUser, nil
+}
+
+func (a AuthRepository) List(ctx context.Context, query domain.UserQueryParam) ([]domain.User, error) {
+	callerInfo := "[AuthRepository.List]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	params := make([]any, 0)
+	listQuery := `SELECT id, email, name, verified_at, ` + rolesColumn + `, suspended_at, created_at FROM users WHERE deleted_at IS NULL`
+
+	if query.Search != "" {
+		params = append(params, fmt.Sprintf("%%%s%%", query.Search))
+		listQuery = fmt.Sprintf("%s AND (email ILIKE $%d OR name ILIKE $%d)", listQuery, len(params), len(params))
+	}
+
+	params = append(params, query.Limit, query.Offset)
+	listQuery = fmt.Sprintf("%s ORDER BY created_at DESC LIMIT $%d OFFSET $%d", listQuery, len(params)-1, len(params))
+
+	rows, err := a.db.Query(ctx, listQuery, params...)
+	if err != nil {
+		l.Error("failed to query", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0)
+	for rows.Next() {
+		var mUser user
+		err = rows.Scan(&mUser.ID, &mUser.Email, &mUser.Name, &mUser.VerifiedAt, &mUser.Roles, &mUser.SuspendedAt, &mUser.CreatedAt)
+		if err != nil {
+			l.Error("failed to scan user", zap.Error(err))
+			return nil, err
+		}
+
+		users = append(users, domain.User{
+			ID:        mUser.ID,
+			Email:     mUser.Email,
+			Name:      mUser.Name,
+			Verified:  mUser.VerifiedAt.Valid,
+			Roles:     toRoles(mUser.Roles),
+			Suspended: mUser.SuspendedAt.Valid,
+			CreatedAt: mUser.CreatedAt,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		l.Error("failed to scan user", zap.Error(err))
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (a AuthRepository) Update(ctx context.Context, dUser domain.User) (domain.User, error) {
+	callerInfo := "[AuthRepository.Update]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE users SET name = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+	cmd, err := a.db.Exec(ctx, updateQuery, dUser.Name, time.Now(), dUser.ID)
+	if err != nil {
+		l.Error("failed to update user", zap.Error(err))
+		return dUser, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("user not found")
+		return dUser, domain.UserNotFoundError
 	}
 
 	return dUser, nil
 }
 
+func (a AuthRepository) UpdatePassword(
+	ctx context.Context,
+	userID ulid.ULID,
+	password string,
+	txs ...pgx.Tx,
+) (pgx.Tx, error) {
+	callerInfo := "[AuthRepository.UpdatePassword]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = a.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	updateQuery := `UPDATE users SET password = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL`
+	cmd, err := tx.Exec(ctx, updateQuery, password, time.Now(), userID)
+	if err != nil {
+		l.Error("failed to update password", zap.Error(err))
+		return tx, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("user not found")
+		return tx, domain.UserNotFoundError
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+// MarkVerified sets verified_at once, as long as the email address
+// still matches the one the verification token was issued for.
+func (a AuthRepository) MarkVerified(ctx context.Context, userID ulid.ULID, email string, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AuthRepository.MarkVerified]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = a.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	updateQuery := `UPDATE users SET verified_at = COALESCE(verified_at, $1), updated_at = $1 WHERE id = $2 AND email = $3 AND deleted_at IS NULL`
+	cmd, err := tx.Exec(ctx, updateQuery, time.Now(), userID, email)
+	if err != nil {
+		l.Error("failed to verify user", zap.Error(err))
+		return tx, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("user not found for verification")
+		return tx, domain.ErrInvalidVerificationToken
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+// Suspend keeps a user from logging in, suspending an already suspended user keeps the original time.
+func (a AuthRepository) Suspend(ctx context.Context, userID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AuthRepository.Suspend]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = a.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	updateQuery := `UPDATE users SET suspended_at = COALESCE(suspended_at, $1), updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	cmd, err := tx.Exec(ctx, updateQuery, time.Now(), userID)
+	if err != nil {
+		l.Error("failed to suspend user", zap.Error(err))
+		return tx, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("user not found")
+		return tx, domain.UserNotFoundError
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (a AuthRepository) Delete(ctx context.Context, userID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[AuthRepository.Delete]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = a.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	deleteQuery := `UPDATE users SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	cmd, err := tx.Exec(ctx, deleteQuery, time.Now(), userID)
+	if err != nil {
+		l.Error("failed to delete user", zap.Error(err))
+		return tx, err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("user not found")
+		return tx, domain.UserNotFoundError
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (a AuthRepository) TxBegin(ctx context.Context) (pgx.Tx, error) {
+	callerInfo := "[AuthRepository.TxBegin]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (a AuthRepository) TxCommit(ctx context.Context, tx pgx.Tx) error {
+	callerInfo := "[AuthRepository.TxCommit]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func toRoles(roles []string) []domain.Role {
+	dRoles := make([]domain.Role, len(roles))
+	for i, role := range roles {
+		dRoles[i] = domain.Role(role)
+	}
+
+	return dRoles
+}
+
 var _ AuthRepositoryContract = (*AuthRepository)(nil)
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type BlockRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBlockRepository(db *pgxpool.Pool) *BlockRepository {
+	return &BlockRepository{
+		db: db,
+	}
+}
+
+// Create blocks a user, blocking an already blocked user keeps the original block.
+func (b BlockRepository) Create(ctx context.Context, blockerID, blockedID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[BlockRepository.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = b.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return nil, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	insertQuery := `INSERT INTO user_blocks (blocker_id, blocked_id, created_at) VALUES ($1, $2, $3)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+	_, err = tx.Exec(ctx, insertQuery, blockerID, blockedID, time.Now())
+	if err != nil {
+		l.Error("failed to insert block", zap.Error(err))
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (b BlockRepository) Delete(ctx context.Context, blockerID, blockedID ulid.ULID) error {
+	callerInfo := "[BlockRepository.Delete]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	deleteQuery := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	cmd, err := b.db.Exec(ctx, deleteQuery, blockerID, blockedID)
+	if err != nil {
+		l.Error("failed to delete block", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("block not found")
+		return domain.ErrBlockNotFound
+	}
+
+	return nil
+}
+
+// List returns the users blocked by blockerID, blocked users who deleted their account are left out.
+func (b BlockRepository) List(ctx context.Context, blockerID ulid.ULID) ([]domain.Block, error) {
+	callerInfo := "[BlockRepository.List]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT b.blocker_id, b.blocked_id, u.name, b.created_at
+		FROM user_blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1 AND u.deleted_at IS NULL
+		ORDER BY b.created_at DESC`
+	rows, err := b.db.Query(ctx, query, blockerID)
+	if err != nil {
+		l.Error("failed to get blocks", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	blocks := make([]domain.Block, 0)
+	for rows.Next() {
+		var mBlock userBlock
+		err = rows.Scan(
+			&mBlock.BlockerID,
+			&mBlock.BlockedID,
+			&mBlock.BlockedName,
+			&mBlock.CreatedAt,
+		)
+		if err != nil {
+			l.Error("failed to scan block", zap.Error(err))
+			return nil, err
+		}
+
+		blocks = append(blocks, domain.Block{
+			BlockerID: mBlock.BlockerID,
+			Blocked: domain.User{
+				ID:   mBlock.BlockedID,
+				Name: mBlock.BlockedName,
+			},
+			CreatedAt: mBlock.CreatedAt,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		l.Error("failed to iterate blocks", zap.Error(err))
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (b BlockRepository) IsBlocked(ctx context.Context, userID, otherUserID ulid.ULID) (bool, error) {
+	callerInfo := "[BlockRepository.IsBlocked]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT EXISTS (
+		SELECT 1 FROM user_blocks
+		WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+	)`
+
+	var blocked bool
+	err := b.db.QueryRow(ctx, query, userID, otherUserID).Scan(&blocked)
+	if err != nil {
+		l.Error("failed to check block", zap.Error(err))
+		return false, err
+	}
+
+	return blocked, nil
+}
+
+var _ BlockRepositoryContract = (*BlockRepository)(nil)
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+)
+
+// LoginAttemptRepository writes every time as UTC, lockouts are compared with the current time
+// in Go to tell the client when to try again.
+type LoginAttemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLoginAttemptRepository(db *pgxpool.Pool) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		db: db,
+	}
+}
+
+// LockedUntil returns the latest lockout among the given keys,
+// or the zero time when none of them is locked.
+func (a LoginAttemptRepository) LockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	callerInfo := "[LoginAttemptRepository.LockedUntil]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var lockedUntil sql.NullTime
+	query := `SELECT MAX(locked_until) FROM login_attempts WHERE key = ANY($1) AND locked_until > $2`
+	err := a.db.QueryRow(ctx, query, keys, timestamp(time.Now())).Scan(&lockedUntil)
+	if err != nil {
+		l.Error("failed to get lockout", zap.Error(err))
+		return time.Time{}, err
+	}
+
+	return lockedUntil.Time, nil
+}
+
+// RecordFailure counts a failed login for the key and returns the number of consecutive failures.
+// Failures older than windowStart are forgotten and the count starts again from one.
+func (a LoginAttemptRepository) RecordFailure(ctx context.Context, key string, windowStart time.Time) (int, error) {
+	callerInfo := "[LoginAttemptRepository.RecordFailure]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var failedCount int
+	upsertQuery := `INSERT INTO login_attempts (key, failed_count, last_failed_at, locked_until, created_at, updated_at)
+		VALUES ($1, 1, $2, NULL, $2, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failed_count = CASE WHEN login_attempts.last_failed_at < $3 THEN 1 ELSE login_attempts.failed_count + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING failed_count`
+	err := a.db.QueryRow(ctx, upsertQuery, key, timestamp(time.Now()), timestamp(windowStart)).Scan(&failedCount)
+	if err != nil {
+		l.Error("failed to record login failure", zap.Error(err))
+		return 0, err
+	}
+
+	return failedCount, nil
+}
+
+func (a LoginAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	callerInfo := "[LoginAttemptRepository.Lock]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE login_attempts SET locked_until = $1, updated_at = $2 WHERE key = $3`
+	_, err := a.db.Exec(ctx, updateQuery, timestamp(until), timestamp(time.Now()), key)
+	if err != nil {
+		l.Error("failed to lock key", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// Reset forgets the failures of the key after a successful login
+// and cleans up entries that are neither locked nor inside the window anymore.
+func (a LoginAttemptRepository) Reset(ctx context.Context, key string, windowStart time.Time) error {
+	callerInfo := "[LoginAttemptRepository.Reset]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	deleteQuery := `DELETE FROM login_attempts
+		WHERE key = $1 OR (last_failed_at < $2 AND (locked_until IS NULL OR locked_until < $3))`
+	_, err := a.db.Exec(ctx, deleteQuery, key, timestamp(windowStart), timestamp(time.Now()))
+	if err != nil {
+		l.Error("failed to reset login attempts", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+var _ LoginAttemptRepositoryContract = (*LoginAttemptRepository)(nil)
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type MFARepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMFARepository(db *pgxpool.Pool) *MFARepository {
+	return &MFARepository{
+		db: db,
+	}
+}
+
+func (m MFARepository) Get(ctx context.Context, userID ulid.ULID) (domain.UserMFA, error) {
+	callerInfo := "[MFARepository.Get]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var mMFA userMFA
+	query := `SELECT user_id, secret, last_used_step, enabled_at FROM user_mfa WHERE user_id = $1`
+	err := m.db.QueryRow(ctx, query, userID).Scan(&mMFA.UserID, &mMFA.Secret, &mMFA.LastUsedStep, &mMFA.EnabledAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("mfa not set up", zap.Error(err))
+			return domain.UserMFA{}, domain.ErrMFANotSetUp
+		}
+		l.Error("failed to get mfa", zap.Error(err))
+		return domain.UserMFA{}, err
+	}
+
+	dMFA := domain.UserMFA{
+		UserID:       mMFA.UserID,
+		Secret:       mMFA.Secret,
+		Enabled:      mMFA.EnabledAt.Valid,
+		LastUsedStep: mMFA.LastUsedStep,
+	}
+
+	return dMFA, nil
+}
+
+// Setup stores a new secret waiting for confirmation, replacing a previous unconfirmed one.
+// Once two-factor authentication is enabled the secret can't be replaced anymore.
+func (m MFARepository) Setup(ctx context.Context, userID ulid.ULID, secret string) error {
+	callerInfo := "[MFARepository.Setup]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	upsertQuery := `INSERT INTO user_mfa (user_id, secret, last_used_step, enabled_at, created_at, updated_at)
+		VALUES ($1, $2, 0, NULL, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = EXCLUDED.updated_at
+		WHERE user_mfa.enabled_at IS NULL`
+	cmd, err := m.db.Exec(ctx, upsertQuery, userID, secret, time.Now())
+	if err != nil {
+		l.Error("failed to store mfa secret", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("mfa already enabled")
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+// Enable confirms the pending secret and replaces the recovery codes of the user.
+func (m MFARepository) Enable(ctx context.Context, userID ulid.ULID, step int64, recoveryCodeHashes []string) error {
+	callerInfo := "[MFARepository.Enable]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	now := time.Now()
+
+	enableQuery := `UPDATE user_mfa SET enabled_at = $1, last_used_step = $2, updated_at = $1 WHERE user_id = $3 AND enabled_at IS NULL`
+	cmd, err := tx.Exec(ctx, enableQuery, now, step, userID)
+	if err != nil {
+		l.Error("failed to enable mfa", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("mfa already enabled")
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	deleteQuery := `DELETE FROM mfa_recovery_codes WHERE user_id = $1`
+	_, err = tx.Exec(ctx, deleteQuery, userID)
+	if err != nil {
+		l.Error("failed to delete recovery codes", zap.Error(err))
+		return err
+	}
+
+	rows := make([][]any, len(recoveryCodeHashes))
+	for i, codeHash := range recoveryCodeHashes {
+		rows[i] = []any{id.New(), userID, codeHash, nil, now}
+	}
+
+	columns := []string{"id", "user_id", "code_hash", "used_at", "created_at"}
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"mfa_recovery_codes"}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		l.Error("failed to insert recovery codes", zap.Error(err))
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// UseStep records the time step of an accepted code. A code of the same
+// or an earlier step is a replay and is rejected with ErrInvalidMFACode.
+func (m MFARepository) UseStep(ctx context.Context, userID ulid.ULID, step int64) error {
+	callerInfo := "[MFARepository.UseStep]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE user_mfa SET last_used_step = $1, updated_at = $2 WHERE user_id = $3 AND last_used_step < $1 AND enabled_at IS NOT NULL`
+	cmd, err := m.db.Exec(ctx, updateQuery, step, time.Now(), userID)
+	if err != nil {
+		l.Error("failed to update last used step", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("totp code already used")
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+func (m MFARepository) UseRecoveryCode(ctx context.Context, userID ulid.ULID, codeHash string) error {
+	callerInfo := "[MFARepository.UseRecoveryCode]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE mfa_recovery_codes SET used_at = $1 WHERE user_id = $2 AND code_hash = $3 AND used_at IS NULL`
+	cmd, err := m.db.Exec(ctx, updateQuery, time.Now(), userID, codeHash)
+	if err != nil {
+		l.Error("failed to use recovery code", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("recovery code not found or already used")
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+var _ MFARepositoryContract = (*MFARepository)(nil)
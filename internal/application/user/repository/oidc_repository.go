@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type OIDCRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOIDCRepository(db *pgxpool.Pool) *OIDCRepository {
+	return &OIDCRepository{
+		db: db,
+	}
+}
+
+// CreateState stores a pending login and removes the expired ones of abandoned logins.
+func (o OIDCRepository) CreateState(ctx context.Context, state domain.OIDCLoginState) error {
+	callerInfo := "[OIDCRepository.CreateState]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	now := time.Now()
+
+	cleanupQuery := `DELETE FROM oidc_login_states WHERE expires_at < $1`
+	_, err := o.db.Exec(ctx, cleanupQuery, now)
+	if err != nil {
+		l.Error("failed to delete expired login states", zap.Error(err))
+		return err
+	}
+
+	insertQuery := `INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err = o.db.Exec(ctx, insertQuery, state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt, now)
+	if err != nil {
+		l.Error("failed to insert login state", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// ConsumeState deletes and returns a pending login, so every state can only be used once.
+// Expired states are not found, they are left to the cleanup in CreateState.
+func (o OIDCRepository) ConsumeState(ctx context.Context, stateHash string) (domain.OIDCLoginState, error) {
+	callerInfo := "[OIDCRepository.ConsumeState]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var state domain.OIDCLoginState
+	// expires_at has no time zone, postgres compares it with the current time written the same way
+	deleteQuery := `DELETE FROM oidc_login_states WHERE state_hash = $1 AND expires_at > $2
+		RETURNING state_hash, nonce, code_verifier, expires_at`
+	err := o.db.QueryRow(ctx, deleteQuery, stateHash, time.Now()).Scan(
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("login state not found or expired", zap.Error(err))
+			return domain.OIDCLoginState{}, domain.ErrInvalidOIDCState
+		}
+		l.Error("failed to consume login state", zap.Error(err))
+		return domain.OIDCLoginState{}, err
+	}
+
+	return state, nil
+}
+
+// GetIdentity returns the identity of an issuer's subject, identities of deleted users are ignored.
+func (o OIDCRepository) GetIdentity(ctx context.Context, issuer, subject string) (domain.UserIdentity, error) {
+	callerInfo := "[OIDCRepository.GetIdentity]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var mIdentity userIdentity
+	query := `SELECT i.id, i.user_id, i.issuer, i.subject, i.email, i.created_at
+		FROM user_identities i
+		JOIN users u ON u.id = i.user_id
+		WHERE i.issuer = $1 AND i.subject = $2 AND u.deleted_at IS NULL`
+	err := o.db.QueryRow(ctx, query, issuer, subject).Scan(
+		&mIdentity.ID,
+		&mIdentity.UserID,
+		&mIdentity.Issuer,
+		&mIdentity.Subject,
+		&mIdentity.Email,
+		&mIdentity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("identity not found", zap.Error(err))
+			return domain.UserIdentity{}, domain.ErrIdentityNotFound
+		}
+		l.Error("failed to get identity", zap.Error(err))
+		return domain.UserIdentity{}, err
+	}
+
+	dIdentity := domain.UserIdentity{
+		ID:        mIdentity.ID,
+		UserID:    mIdentity.UserID,
+		Issuer:    mIdentity.Issuer,
+		Subject:   mIdentity.Subject,
+		Email:     mIdentity.Email,
+		CreatedAt: mIdentity.CreatedAt,
+	}
+
+	return dIdentity, nil
+}
+
+// LinkIdentity links an issuer's subject to a user, replacing the link to a previously deleted user.
+func (o OIDCRepository) LinkIdentity(ctx context.Context, dIdentity domain.UserIdentity) (domain.UserIdentity, error) {
+	callerInfo := "[OIDCRepository.LinkIdentity]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	mIdentity := userIdentity{
+		ID:        id.New(),
+		UserID:    dIdentity.UserID,
+		Issuer:    dIdentity.Issuer,
+		Subject:   dIdentity.Subject,
+		Email:     dIdentity.Email,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	upsertQuery := `INSERT INTO user_identities (id, user_id, issuer, subject, email, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (issuer, subject) DO UPDATE SET user_id = EXCLUDED.user_id, email = EXCLUDED.email, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`
+	err := o.db.QueryRow(
+		ctx,
+		upsertQuery,
+		mIdentity.ID,
+		mIdentity.UserID,
+		mIdentity.Issuer,
+		mIdentity.Subject,
+		mIdentity.Email,
+		mIdentity.CreatedAt,
+		mIdentity.UpdatedAt,
+	).Scan(&mIdentity.ID, &mIdentity.CreatedAt)
+	if err != nil {
+		l.Error("failed to link identity", zap.Error(err))
+		return dIdentity, err
+	}
+
+	dIdentity.ID = mIdentity.ID
+	dIdentity.CreatedAt = mIdentity.CreatedAt
+	return dIdentity, nil
+}
+
+var _ OIDCRepositoryContract = (*OIDCRepository)(nil)
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type PasswordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		db: db,
+	}
+}
+
+// Create stores a new reset token and invalidates every reset token
+// previously issued to the same user, so only the latest email works.
+func (p PasswordResetRepository) Create(
+	ctx context.Context,
+	dToken domain.PasswordResetToken,
+) (domain.PasswordResetToken, error) {
+	callerInfo := "[PasswordResetRepository.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return dToken, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	mToken := passwordResetToken{
+		ID:        id.New(),
+		UserID:    dToken.UserID,
+		TokenHash: dToken.TokenHash,
+		ExpiresAt: dToken.ExpiresAt,
+		UsedAt: sql.NullTime{
+			Valid: false,
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	invalidateQuery := `UPDATE password_reset_tokens SET used_at = $1, updated_at = $1 WHERE user_id = $2 AND used_at IS NULL`
+	_, err = tx.Exec(ctx, invalidateQuery, mToken.CreatedAt, mToken.UserID)
+	if err != nil {
+		l.Error("failed to invalidate previous reset tokens", zap.Error(err))
+		return dToken, err
+	}
+
+	insertQuery := `INSERT INTO password_reset_tokens (id, user_id, token_hash, expires_at, used_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err = tx.Exec(
+		ctx,
+		insertQuery,
+		mToken.ID,
+		mToken.UserID,
+		mToken.TokenHash,
+		mToken.ExpiresAt,
+		mToken.UsedAt,
+		mToken.CreatedAt,
+		mToken.UpdatedAt,
+	)
+	if err != nil {
+		l.Error("failed to insert reset token", zap.Error(err))
+		return dToken, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return dToken, err
+	}
+
+	dToken.ID = mToken.ID
+	dToken.CreatedAt = mToken.CreatedAt
+	return dToken, nil
+}
+
+// GetByHash returns the reset token and locks it until tx ends, tokens that were used or
+// expired are not found. The expiry is checked by postgres, expires_at has no time zone
+// and only compares correctly with a time written the same way.
+func (p PasswordResetRepository) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+	tx pgx.Tx,
+) (domain.PasswordResetToken, error) {
+	callerInfo := "[PasswordResetRepository.GetByHash]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var mToken passwordResetToken
+	query := `SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, tokenHash, time.Now()).Scan(
+		&mToken.ID,
+		&mToken.UserID,
+		&mToken.TokenHash,
+		&mToken.ExpiresAt,
+		&mToken.UsedAt,
+		&mToken.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("reset token not found, used or expired", zap.Error(err))
+			return domain.PasswordResetToken{}, domain.ErrInvalidResetToken
+		}
+		l.Error("failed to get reset token", zap.Error(err))
+		return domain.PasswordResetToken{}, err
+	}
+
+	dToken := domain.PasswordResetToken{
+		ID:        mToken.ID,
+		UserID:    mToken.UserID,
+		TokenHash: mToken.TokenHash,
+		ExpiresAt: mToken.ExpiresAt,
+		CreatedAt: mToken.CreatedAt,
+	}
+	if mToken.UsedAt.Valid {
+		dToken.UsedAt = mToken.UsedAt.Time
+	}
+
+	return dToken, nil
+}
+
+func (p PasswordResetRepository) MarkUsed(ctx context.Context, tokenID ulid.ULID, tx pgx.Tx) error {
+	callerInfo := "[PasswordResetRepository.MarkUsed]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE password_reset_tokens SET used_at = $1, updated_at = $1 WHERE id = $2`
+	_, err := tx.Exec(ctx, updateQuery, time.Now(), tokenID)
+	if err != nil {
+		l.Error("failed to mark reset token as used", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (p PasswordResetRepository) TxBegin(ctx context.Context) (pgx.Tx, error) {
+	callerInfo := "[PasswordResetRepository.TxBegin]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := p.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (p PasswordResetRepository) TxCommit(ctx context.Context, tx pgx.Tx) error {
+	callerInfo := "[PasswordResetRepository.TxCommit]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+var _ PasswordResetRepositoryContract = (*PasswordResetRepository)(nil)
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/oklog/ulid/v2"
 
 	"cats-social/internal/domain"
@@ -12,4 +14,73 @@ type AuthRepositoryContract interface {
 	Create(ctx context.Context, user domain.User) (domain.User, error)
 	GetByEmail(ctx context.Context, email string) (domain.User, error)
 	Get(ctx context.Context, userID ulid.ULID) (domain.User, error)
+	List(ctx context.Context, query domain.UserQueryParam) ([]domain.User, error)
+	Update(ctx context.Context, user domain.User) (domain.User, error)
+	UpdatePassword(ctx context.Context, userID ulid.ULID, password string, tx ...pgx.Tx) (pgx.Tx, error)
+	MarkVerified(ctx context.Context, userID ulid.ULID, email string, tx ...pgx.Tx) (pgx.Tx, error)
+	Suspend(ctx context.Context, userID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	Delete(ctx context.Context, userID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	TxBegin(ctx context.Context) (pgx.Tx, error)
+	TxCommit(ctx context.Context, tx pgx.Tx) error
+}
+
+type TokenRepositoryContract interface {
+	Create(ctx context.Context, token domain.RefreshToken, tx ...pgx.Tx) (domain.RefreshToken, pgx.Tx, error)
+	GetByHash(ctx context.Context, tokenHash string, tx pgx.Tx) (domain.RefreshToken, error)
+	MarkUsed(ctx context.Context, tokenID ulid.ULID, tx pgx.Tx) error
+	RevokeFamily(ctx context.Context, familyID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	RevokeFamilyByHash(ctx context.Context, userID ulid.ULID, tokenHash string) error
+	RevokeAllByUser(ctx context.Context, userID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	TxBegin(ctx context.Context) (pgx.Tx, error)
+	TxCommit(ctx context.Context, tx pgx.Tx) error
+}
+
+type RevocationRepositoryContract interface {
+	IsRevoked(ctx context.Context, token domain.AccessToken) (bool, error)
+	Revoke(ctx context.Context, token domain.AccessToken) error
+	RevokeAll(ctx context.Context, userID ulid.ULID) error
+	RevokeSession(ctx context.Context, userID, sessionID ulid.ULID) error
+}
+
+type PasswordResetRepositoryContract interface {
+	Create(ctx context.Context, token domain.PasswordResetToken) (domain.PasswordResetToken, error)
+	GetByHash(ctx context.Context, tokenHash string, tx pgx.Tx) (domain.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, tokenID ulid.ULID, tx pgx.Tx) error
+	TxBegin(ctx context.Context) (pgx.Tx, error)
+	TxCommit(ctx context.Context, tx pgx.Tx) error
+}
+
+type LoginAttemptRepositoryContract interface {
+	LockedUntil(ctx context.Context, keys ...string) (time.Time, error)
+	RecordFailure(ctx context.Context, key string, windowStart time.Time) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string, windowStart time.Time) error
+}
+
+type MFARepositoryContract interface {
+	Get(ctx context.Context, userID ulid.ULID) (domain.UserMFA, error)
+	Setup(ctx context.Context, userID ulid.ULID, secret string) error
+	Enable(ctx context.Context, userID ulid.ULID, step int64, recoveryCodeHashes []string) error
+	UseStep(ctx context.Context, userID ulid.ULID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID ulid.ULID, codeHash string) error
+}
+
+type OIDCRepositoryContract interface {
+	CreateState(ctx context.Context, state domain.OIDCLoginState) error
+	ConsumeState(ctx context.Context, stateHash string) (domain.OIDCLoginState, error)
+	GetIdentity(ctx context.Context, issuer, subject string) (domain.UserIdentity, error)
+	LinkIdentity(ctx context.Context, identity domain.UserIdentity) (domain.UserIdentity, error)
+}
+
+type SessionRepositoryContract interface {
+	Create(ctx context.Context, session domain.Session, tx ...pgx.Tx) (domain.Session, pgx.Tx, error)
+	List(ctx context.Context, userID ulid.ULID) ([]domain.Session, error)
+	Touch(ctx context.Context, sessionID ulid.ULID, ipAddress string) error
+}
+
+type BlockRepositoryContract interface {
+	Create(ctx context.Context, blockerID, blockedID ulid.ULID, tx ...pgx.Tx) (pgx.Tx, error)
+	Delete(ctx context.Context, blockerID, blockedID ulid.ULID) error
+	List(ctx context.Context, blockerID ulid.ULID) ([]domain.Block, error)
+	IsBlocked(ctx context.Context, userID, otherUserID ulid.ULID) (bool, error)
 }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const revocationCacheSweepInterval = time.Minute
+
+type revocationEntry struct {
+	userID    ulid.ULID
+	sessionID ulid.ULID
+	revoked   bool
+	expiresAt time.Time
+}
+
+// revocationCache keeps the outcome of revocation lookups per jti.
+// Revoked tokens stay cached until they expire, tokens that are still valid
+// are only trusted for the configured TTL, since another process may revoke them.
+type revocationCache struct {
+	mu        sync.RWMutex
+	entries   map[ulid.ULID]revocationEntry
+	ttl       time.Duration
+	lastSweep time.Time
+}
+
+func newRevocationCache(ttl time.Duration) *revocationCache {
+	return &revocationCache{
+		entries:   make(map[ulid.ULID]revocationEntry),
+		ttl:       ttl,
+		lastSweep: time.Now(),
+	}
+}
+
+func (r *revocationCache) get(jti ulid.ULID) (bool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, ok := r.entries[jti]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return false, false
+	}
+
+	return entry.revoked, true
+}
+
+func (r *revocationCache) set(jti, userID, sessionID ulid.ULID, revoked bool, tokenExpiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	expiresAt := tokenExpiresAt
+	if !revoked && now.Add(r.ttl).Before(expiresAt) {
+		expiresAt = now.Add(r.ttl)
+	}
+
+	r.entries[jti] = revocationEntry{
+		userID:    userID,
+		sessionID: sessionID,
+		revoked:   revoked,
+		expiresAt: expiresAt,
+	}
+
+	if now.Sub(r.lastSweep) > revocationCacheSweepInterval {
+		for k, v := range r.entries {
+			if now.After(v.expiresAt) {
+				delete(r.entries, k)
+			}
+		}
+		r.lastSweep = now
+	}
+}
+
+func (r *revocationCache) deleteUser(userID ulid.ULID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.entries {
+		if v.userID == userID {
+			delete(r.entries, k)
+		}
+	}
+}
+
+func (r *revocationCache) deleteSession(sessionID ulid.ULID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k, v := range r.entries {
+		if v.sessionID == sessionID {
+			delete(r.entries, k)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type RevocationRepository struct {
+	db    *pgxpool.Pool
+	cache *revocationCache
+}
+
+func NewRevocationRepository(db *pgxpool.Pool, cacheTTL time.Duration) *RevocationRepository {
+	return &RevocationRepository{
+		db:    db,
+		cache: newRevocationCache(cacheTTL),
+	}
+}
+
+func (r RevocationRepository) IsRevoked(ctx context.Context, token domain.AccessToken) (bool, error) {
+	callerInfo := "[RevocationRepository.IsRevoked]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	if revoked, ok := r.cache.get(token.ID); ok {
+		return revoked, nil
+	}
+
+	// a token is revoked on its own, with its session or by a later logout from every device,
+	// the jti timestamp tells whether it was issued before that logout
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+			OR EXISTS (SELECT 1 FROM sessions WHERE id = $3 AND revoked_at IS NOT NULL),
+		(SELECT revoked_before FROM user_token_revocations WHERE user_id = $2)`
+
+	var (
+		revoked bool
+		cutoff  sql.NullTime
+	)
+	err := r.db.QueryRow(ctx, query, token.ID, token.UserID, token.SessionID).Scan(&revoked, &cutoff)
+	if err != nil {
+		l.Error("failed to check token revocation", zap.Error(err))
+		return false, err
+	}
+
+	if cutoff.Valid && issuedBefore(token.ID, cutoff.Time) {
+		revoked = true
+	}
+
+	r.cache.set(token.ID, token.UserID, token.SessionID, revoked, token.ExpiresAt)
+	return revoked, nil
+}
+
+func (r RevocationRepository) Revoke(ctx context.Context, token domain.AccessToken) error {
+	callerInfo := "[RevocationRepository.Revoke]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	now := time.Now()
+
+	insertQuery := `INSERT INTO revoked_tokens (jti, user_id, expires_at, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (jti) DO NOTHING`
+	_, err = tx.Exec(ctx, insertQuery, token.ID, token.UserID, token.ExpiresAt, now)
+	if err != nil {
+		l.Error("failed to insert revoked token", zap.Error(err))
+		return err
+	}
+
+	// expired tokens are rejected by signature validation anyway
+	cleanupQuery := `DELETE FROM revoked_tokens WHERE expires_at < $1`
+	_, err = tx.Exec(ctx, cleanupQuery, now)
+	if err != nil {
+		l.Error("failed to delete expired revoked tokens", zap.Error(err))
+		return err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	r.cache.set(token.ID, token.UserID, token.SessionID, true, token.ExpiresAt)
+	return nil
+}
+
+func (r RevocationRepository) RevokeAll(ctx context.Context, userID ulid.ULID) error {
+	callerInfo := "[RevocationRepository.RevokeAll]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	now := time.Now()
+	upsertQuery := `INSERT INTO user_token_revocations (user_id, revoked_before, created_at, updated_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before, updated_at = EXCLUDED.updated_at`
+
+	_, err := r.db.Exec(ctx, upsertQuery, userID, revocationCutoff(now), now, now)
+	if err != nil {
+		l.Error("failed to revoke user tokens", zap.Error(err))
+		return err
+	}
+
+	r.cache.deleteUser(userID)
+	return nil
+}
+
+// RevokeSession revokes every access token issued for a session of the user.
+func (r RevocationRepository) RevokeSession(ctx context.Context, userID, sessionID ulid.ULID) error {
+	callerInfo := "[RevocationRepository.RevokeSession]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE sessions SET revoked_at = $1 WHERE id = $2 AND user_id = $3 AND revoked_at IS NULL`
+	cmd, err := r.db.Exec(ctx, updateQuery, timestamp(time.Now()), sessionID, userID)
+	if err != nil {
+		l.Error("failed to revoke session", zap.Error(err))
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		l.Info("session not found")
+		return domain.ErrSessionNotFound
+	}
+
+	r.cache.deleteSession(sessionID)
+	return nil
+}
+
+// revocationCutoff returns the revoked_before stored by RevokeAll. Access tokens only tell the
+// millisecond they were issued in through their jti, so the cut-off is truncated alike and a token
+// issued in the same millisecond as the logout, such as the one handed out by a password change, stays valid.
+func revocationCutoff(now time.Time) time.Time {
+	return timestamp(now).Truncate(time.Millisecond)
+}
+
+// issuedBefore reports whether the token with the jti was issued before the cut-off.
+func issuedBefore(tokenID ulid.ULID, cutoff time.Time) bool {
+	return ulid.Time(tokenID.Time()).Before(cutoff)
+}
+
+var _ RevocationRepositoryContract = (*RevocationRepository)(nil)
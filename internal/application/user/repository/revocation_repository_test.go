@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+func tokenIssuedAt(t *testing.T, issuedAt time.Time) ulid.ULID {
+	t.Helper()
+
+	tokenID, err := ulid.New(ulid.Timestamp(issuedAt), rand.Reader)
+	if err != nil {
+		t.Fatalf("ulid.New: %v", err)
+	}
+
+	return tokenID
+}
+
+func TestIssuedBeforeRevocationCutoff(t *testing.T) {
+	// the revocation happens late within a millisecond, the way a fresh time.Now() usually does
+	revokedAt := time.Date(2024, 5, 1, 10, 0, 0, 123_987_000, time.UTC)
+	cutoff := revocationCutoff(revokedAt)
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		want     bool
+	}{
+		{
+			name:     "issued in the same millisecond right after the revocation",
+			issuedAt: revokedAt.Add(5 * time.Microsecond),
+			want:     false,
+		},
+		{
+			name:     "issued in a later millisecond",
+			issuedAt: revokedAt.Add(time.Millisecond),
+			want:     false,
+		},
+		{
+			name:     "issued in an earlier millisecond",
+			issuedAt: revokedAt.Add(-time.Millisecond),
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuedBefore(tokenIssuedAt(t, tt.issuedAt), cutoff); got != tt.want {
+				t.Fatalf("issuedBefore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeThenIssueInSameMillisecond(t *testing.T) {
+	// a password change revokes every token and issues a new one right away
+	now := time.Now()
+	cutoff := revocationCutoff(now)
+	tokenID := tokenIssuedAt(t, now)
+
+	if issuedBefore(tokenID, cutoff) {
+		t.Fatalf("token issued at %s is revoked by cut-off %s", ulid.Time(tokenID.Time()), cutoff)
+	}
+}
+
+func TestSessionsAroundRevokeAllOutsideUTC(t *testing.T) {
+	for _, loc := range testZones {
+		t.Run(loc.String(), func(t *testing.T) {
+			withLocal(t, loc)
+
+			// the times SessionRepository.Create and RevocationRepository.RevokeAll store
+			revokedAt := time.Now()
+			cutoff := storeTimestamp(t, revocationCutoff(revokedAt))
+			before := storeTimestamp(t, timestamp(revokedAt.Add(-time.Minute)))
+			after := storeTimestamp(t, timestamp(revokedAt.Add(time.Minute)))
+
+			// sessions are listed unless revoked_before > created_at
+			if !cutoff.After(before) {
+				t.Fatalf("session created at %s survives the revocation at %s", before, cutoff)
+			}
+			if cutoff.After(after) {
+				t.Fatalf("session created at %s is hidden by the revocation at %s", after, cutoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+// sessionTouchInterval limits how often the last seen time of a session is written.
+const sessionTouchInterval = time.Minute
+
+// SessionRepository writes every time as UTC, created_at is compared with the
+// revoked_before written by RevocationRepository.RevokeAll.
+type SessionRepository struct {
+	db      *pgxpool.Pool
+	mu      *sync.Mutex
+	touched map[ulid.ULID]time.Time
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{
+		db:      db,
+		mu:      &sync.Mutex{},
+		touched: make(map[ulid.ULID]time.Time),
+	}
+}
+
+func (s SessionRepository) Create(ctx context.Context, dSession domain.Session, txs ...pgx.Tx) (domain.Session, pgx.Tx, error) {
+	callerInfo := "[SessionRepository.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = s.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return dSession, nil, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	now := timestamp(time.Now())
+	mSession := session{
+		ID:         dSession.ID,
+		UserID:     dSession.UserID,
+		UserAgent:  dSession.UserAgent,
+		IPAddress:  dSession.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+
+	insertQuery := `INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = tx.Exec(
+		ctx,
+		insertQuery,
+		mSession.ID,
+		mSession.UserID,
+		mSession.UserAgent,
+		mSession.IPAddress,
+		mSession.CreatedAt,
+		mSession.LastSeenAt,
+	)
+	if err != nil {
+		l.Error("failed to insert session", zap.Error(err))
+		return dSession, tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return dSession, nil, err
+		}
+	}
+
+	dSession.CreatedAt = mSession.CreatedAt
+	dSession.LastSeenAt = mSession.LastSeenAt
+	return dSession, tx, nil
+}
+
+// List returns the sessions of a user that can still be used, that is sessions which were
+// not revoked, were not ended by a logout from every device and still have a usable refresh token.
+func (s SessionRepository) List(ctx context.Context, userID ulid.ULID) ([]domain.Session, error) {
+	callerInfo := "[SessionRepository.List]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	query := `SELECT s.id, s.user_id, s.user_agent, s.ip_address, s.created_at, s.last_seen_at
+		FROM sessions s
+		WHERE s.user_id = $1
+			AND s.revoked_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM user_token_revocations r WHERE r.user_id = s.user_id AND r.revoked_before > s.created_at)
+			AND EXISTS (SELECT 1 FROM refresh_tokens t WHERE t.family_id = s.id AND t.used_at IS NULL AND t.revoked_at IS NULL AND t.expires_at > $2)
+		ORDER BY s.last_seen_at DESC`
+	// refresh_tokens.expires_at is written as local time, unlike the session times
+	rows, err := s.db.Query(ctx, query, userID, time.Now())
+	if err != nil {
+		l.Error("failed to get sessions", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]domain.Session, 0)
+	for rows.Next() {
+		var mSession session
+		err = rows.Scan(
+			&mSession.ID,
+			&mSession.UserID,
+			&mSession.UserAgent,
+			&mSession.IPAddress,
+			&mSession.CreatedAt,
+			&mSession.LastSeenAt,
+		)
+		if err != nil {
+			l.Error("failed to scan session", zap.Error(err))
+			return nil, err
+		}
+
+		sessions = append(sessions, domain.Session{
+			ID:         mSession.ID,
+			UserID:     mSession.UserID,
+			UserAgent:  mSession.UserAgent,
+			IPAddress:  mSession.IPAddress,
+			CreatedAt:  mSession.CreatedAt,
+			LastSeenAt: mSession.LastSeenAt,
+		})
+	}
+
+	if err = rows.Err(); err != nil {
+		l.Error("failed to iterate sessions", zap.Error(err))
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Touch updates the last seen time and address of a session, at most once per sessionTouchInterval.
+func (s SessionRepository) Touch(ctx context.Context, sessionID ulid.ULID, ipAddress string) error {
+	callerInfo := "[SessionRepository.Touch]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	now := time.Now()
+	if !s.shouldTouch(sessionID, now) {
+		return nil
+	}
+
+	updateQuery := `UPDATE sessions SET last_seen_at = $1, ip_address = $2 WHERE id = $3 AND revoked_at IS NULL`
+	_, err := s.db.Exec(ctx, updateQuery, timestamp(now), ipAddress, sessionID)
+	if err != nil {
+		l.Error("failed to touch session", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (s SessionRepository) shouldTouch(sessionID ulid.ULID, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.touched[sessionID]) < sessionTouchInterval {
+		return false
+	}
+
+	for k, v := range s.touched {
+		if now.Sub(v) >= sessionTouchInterval {
+			delete(s.touched, k)
+		}
+	}
+	s.touched[sessionID] = now
+
+	return true
+}
+
+var _ SessionRepositoryContract = (*SessionRepository)(nil)
@@ -0,0 +1,10 @@
+package repository
+
+import "time"
+
+// timestamp returns t the way it has to be written to a TIMESTAMP column that is later
+// compared with the current time in Go. The column keeps no time zone, pgx writes the wall
+// clock and reads it back as UTC, so only UTC wall clocks come back as the same instant.
+func timestamp(t time.Time) time.Time {
+	return t.UTC()
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// storeTimestamp writes v to a TIMESTAMP column and reads it back the way pgx does.
+func storeTimestamp(t *testing.T, v time.Time) time.Time {
+	t.Helper()
+
+	m := pgtype.NewMap()
+	buf, err := m.Encode(pgtype.TimestampOID, pgtype.BinaryFormatCode, v, nil)
+	if err != nil {
+		t.Fatalf("encode timestamp: %v", err)
+	}
+
+	var got time.Time
+	if err = m.Scan(pgtype.TimestampOID, pgtype.BinaryFormatCode, buf, &got); err != nil {
+		t.Fatalf("scan timestamp: %v", err)
+	}
+
+	return got
+}
+
+// withLocal runs the test as if the server was in the given zone.
+func withLocal(t *testing.T, loc *time.Location) {
+	t.Helper()
+
+	local := time.Local
+	time.Local = loc
+	t.Cleanup(func() {
+		time.Local = local
+	})
+}
+
+var testZones = []*time.Location{
+	time.FixedZone("UTC+7", 7*60*60),
+	time.FixedZone("UTC-5", -5*60*60),
+}
+
+func TestLockoutOutsideUTC(t *testing.T) {
+	for _, loc := range testZones {
+		t.Run(loc.String(), func(t *testing.T) {
+			withLocal(t, loc)
+
+			// the lockout is stored by Lock and read back by LockedUntil
+			lockedUntil := storeTimestamp(t, timestamp(time.Now().Add(30*time.Second)))
+
+			if retryAfter := time.Until(lockedUntil); retryAfter <= 29*time.Second || retryAfter > 30*time.Second {
+				t.Fatalf("retry after = %s, want about 30s", retryAfter)
+			}
+		})
+	}
+}
+
+func TestLocalTimestampShiftsOutsideUTC(t *testing.T) {
+	withLocal(t, testZones[0])
+
+	now := time.Now()
+	if got := storeTimestamp(t, now); got.Equal(now) {
+		t.Fatal("local wall clock came back as the same instant, the timestamp helper is not needed anymore")
+	}
+	if got := storeTimestamp(t, timestamp(now)); !got.Equal(now.Truncate(time.Microsecond)) {
+		t.Fatalf("stored %s, read back %s", now, got)
+	}
+}
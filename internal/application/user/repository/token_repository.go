@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/id"
+	"cats-social/common/logger"
+	"cats-social/internal/domain"
+)
+
+type TokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenRepository(db *pgxpool.Pool) *TokenRepository {
+	return &TokenRepository{
+		db: db,
+	}
+}
+
+func (t TokenRepository) Create(
+	ctx context.Context,
+	dToken domain.RefreshToken,
+	txs ...pgx.Tx,
+) (domain.RefreshToken, pgx.Tx, error) {
+	callerInfo := "[TokenRepository.Create]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = t.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return dToken, tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	mToken := refreshToken{
+		ID:        id.New(),
+		UserID:    dToken.UserID,
+		FamilyID:  dToken.FamilyID,
+		TokenHash: dToken.TokenHash,
+		ExpiresAt: dToken.ExpiresAt,
+		UsedAt: sql.NullTime{
+			Valid: false,
+		},
+		RevokedAt: sql.NullTime{
+			Valid: false,
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	insertQuery := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err = tx.Exec(
+		ctx,
+		insertQuery,
+		mToken.ID,
+		mToken.UserID,
+		mToken.FamilyID,
+		mToken.TokenHash,
+		mToken.ExpiresAt,
+		mToken.UsedAt,
+		mToken.RevokedAt,
+		mToken.CreatedAt,
+		mToken.UpdatedAt,
+	)
+	if err != nil {
+		l.Error("failed to insert refresh token", zap.Error(err))
+		return dToken, tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return dToken, tx, err
+		}
+	}
+
+	dToken.ID = mToken.ID
+	dToken.CreatedAt = mToken.CreatedAt
+	return dToken, tx, nil
+}
+
+// GetByHash locks the refresh token row for the rest of the transaction,
+// so concurrent rotations of the same token are serialized.
+func (t TokenRepository) GetByHash(ctx context.Context, tokenHash string, tx pgx.Tx) (domain.RefreshToken, error) {
+	callerInfo := "[TokenRepository.GetByHash]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var mToken refreshToken
+	var expired bool
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at, expires_at <= $2
+		FROM refresh_tokens WHERE token_hash = $1 FOR UPDATE`
+	err := tx.QueryRow(ctx, query, tokenHash, time.Now()).Scan(
+		&mToken.ID,
+		&mToken.UserID,
+		&mToken.FamilyID,
+		&mToken.TokenHash,
+		&mToken.ExpiresAt,
+		&mToken.UsedAt,
+		&mToken.RevokedAt,
+		&mToken.CreatedAt,
+		&expired,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			l.Info("refresh token not found", zap.Error(err))
+			return domain.RefreshToken{}, domain.ErrInvalidRefreshToken
+		}
+		l.Error("failed to get refresh token", zap.Error(err))
+		return domain.RefreshToken{}, err
+	}
+
+	dToken := domain.RefreshToken{
+		ID:        mToken.ID,
+		UserID:    mToken.UserID,
+		FamilyID:  mToken.FamilyID,
+		TokenHash: mToken.TokenHash,
+		ExpiresAt: mToken.ExpiresAt,
+		CreatedAt: mToken.CreatedAt,
+		Expired:   expired,
+	}
+	if mToken.UsedAt.Valid {
+		dToken.UsedAt = mToken.UsedAt.Time
+	}
+	if mToken.RevokedAt.Valid {
+		dToken.RevokedAt = mToken.RevokedAt.Time
+	}
+
+	return dToken, nil
+}
+
+func (t TokenRepository) MarkUsed(ctx context.Context, tokenID ulid.ULID, tx pgx.Tx) error {
+	callerInfo := "[TokenRepository.MarkUsed]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	updateQuery := `UPDATE refresh_tokens SET used_at = $1, updated_at = $1 WHERE id = $2`
+	_, err := tx.Exec(ctx, updateQuery, time.Now(), tokenID)
+	if err != nil {
+		l.Error("failed to mark refresh token as used", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (t TokenRepository) RevokeFamily(ctx context.Context, familyID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[TokenRepository.RevokeFamily]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = t.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = $1, updated_at = $1 WHERE family_id = $2 AND revoked_at IS NULL`
+	_, err = tx.Exec(ctx, revokeQuery, time.Now(), familyID)
+	if err != nil {
+		l.Error("failed to revoke refresh token family", zap.Error(err))
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (t TokenRepository) RevokeFamilyByHash(ctx context.Context, userID ulid.ULID, tokenHash string) error {
+	callerInfo := "[TokenRepository.RevokeFamilyByHash]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = $1, updated_at = $1
+		WHERE revoked_at IS NULL AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $2 AND user_id = $3)`
+
+	_, err := t.db.Exec(ctx, revokeQuery, time.Now(), tokenHash, userID)
+	if err != nil {
+		l.Error("failed to revoke refresh token family", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (t TokenRepository) RevokeAllByUser(ctx context.Context, userID ulid.ULID, txs ...pgx.Tx) (pgx.Tx, error) {
+	callerInfo := "[TokenRepository.RevokeAllByUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+
+	if len(txs) == 0 {
+		tx, err = t.db.Begin(ctx)
+		if err != nil {
+			l.Error("failed to begin transaction", zap.Error(err))
+			return tx, err
+		}
+		defer func() {
+			_ = tx.Rollback(ctx)
+		}()
+	} else {
+		tx = txs[0]
+	}
+
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = $1, updated_at = $1 WHERE user_id = $2 AND revoked_at IS NULL`
+	_, err = tx.Exec(ctx, revokeQuery, time.Now(), userID)
+	if err != nil {
+		l.Error("failed to revoke refresh tokens", zap.Error(err))
+		return tx, err
+	}
+
+	if len(txs) == 0 {
+		err = tx.Commit(ctx)
+		if err != nil {
+			l.Error("failed to commit transaction", zap.Error(err))
+			return tx, err
+		}
+	}
+
+	return tx, nil
+}
+
+func (t TokenRepository) TxBegin(ctx context.Context) (pgx.Tx, error) {
+	callerInfo := "[TokenRepository.TxBegin]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := t.db.Begin(ctx)
+	if err != nil {
+		l.Error("failed to begin transaction", zap.Error(err))
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func (t TokenRepository) TxCommit(ctx context.Context, tx pgx.Tx) error {
+	callerInfo := "[TokenRepository.TxCommit]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := tx.Commit(ctx)
+	if err != nil {
+		l.Error("failed to commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+var _ TokenRepositoryContract = (*TokenRepository)(nil)
@@ -8,11 +8,73 @@ import (
 )
 
 type user struct {
+	ID          ulid.ULID
+	Email       string
+	Name        string
+	Password    string
+	VerifiedAt  sql.NullTime
+	Roles       []string
+	MFAEnabled  bool
+	SuspendedAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   sql.NullTime
+}
+
+type refreshToken struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	FamilyID  ulid.ULID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	RevokedAt sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type passwordResetToken struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type userMFA struct {
+	UserID       ulid.ULID
+	Secret       string
+	LastUsedStep int64
+	EnabledAt    sql.NullTime
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type userIdentity struct {
 	ID        ulid.ULID
+	UserID    ulid.ULID
+	Issuer    string
+	Subject   string
 	Email     string
-	Name      string
-	Password  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt sql.NullTime
+}
+
+type session struct {
+	ID         ulid.ULID
+	UserID     ulid.ULID
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	RevokedAt  sql.NullTime
+}
+
+type userBlock struct {
+	BlockerID   ulid.ULID
+	BlockedID   ulid.ULID
+	BlockedName string
+	CreatedAt   time.Time
 }
@@ -2,25 +2,73 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
 	"go.uber.org/zap"
 
+	"cats-social/common/configs"
+	"cats-social/common/id"
 	"cats-social/common/logger"
+	"cats-social/common/mailer"
 	"cats-social/common/security"
 	"cats-social/internal/application/user/repository"
 	"cats-social/internal/domain"
 )
 
+const recoveryCodeCount = 10
+
+const (
+	passwordResetSubject = "Reset your Cats Social password"
+	passwordResetBody    = "Hi %s,\r\n\r\n" +
+		"Use the following token to reset your password:\r\n\r\n%s\r\n\r\n" +
+		"The token expires at %s. If you did not request a password reset, you can ignore this email.\r\n"
+
+	verificationSubject = "Verify your Cats Social email address"
+	verificationBody    = "Hi %s,\r\n\r\n" +
+		"Use the following token to verify your email address:\r\n\r\n%s\r\n\r\n" +
+		"You need a verified email address before you can add cats or request matches.\r\n"
+)
+
 type AuthService struct {
-	authRepository repository.AuthRepositoryContract
-	contextTimeout time.Duration
+	authRepository          repository.AuthRepositoryContract
+	tokenRepository         repository.TokenRepositoryContract
+	revocationRepository    repository.RevocationRepositoryContract
+	sessionRepository       repository.SessionRepositoryContract
+	passwordResetRepository repository.PasswordResetRepositoryContract
+	loginAttemptRepository  repository.LoginAttemptRepositoryContract
+	mfaRepository           repository.MFARepositoryContract
+	loginThrottle           domain.LoginThrottle
+	mailer                  mailer.Mailer
+	contextTimeout          time.Duration
 }
 
-func NewAuthService(timeout time.Duration, authRepository repository.AuthRepositoryContract) *AuthService {
+func NewAuthService(
+	timeout time.Duration,
+	authRepository repository.AuthRepositoryContract,
+	tokenRepository repository.TokenRepositoryContract,
+	revocationRepository repository.RevocationRepositoryContract,
+	sessionRepository repository.SessionRepositoryContract,
+	passwordResetRepository repository.PasswordResetRepositoryContract,
+	loginAttemptRepository repository.LoginAttemptRepositoryContract,
+	mfaRepository repository.MFARepositoryContract,
+	loginThrottle domain.LoginThrottle,
+	mailer mailer.Mailer,
+) *AuthService {
 	authService := &AuthService{
-		authRepository: authRepository,
-		contextTimeout: timeout,
+		authRepository:          authRepository,
+		tokenRepository:         tokenRepository,
+		revocationRepository:    revocationRepository,
+		sessionRepository:       sessionRepository,
+		passwordResetRepository: passwordResetRepository,
+		loginAttemptRepository:  loginAttemptRepository,
+		mfaRepository:           mfaRepository,
+		loginThrottle:           loginThrottle,
+		mailer:                  mailer,
+		contextTimeout:          timeout,
 	}
 
 	return authService
@@ -33,6 +81,8 @@ func (a AuthService) Register(ctx context.Context, user domain.User) (domain.Use
 	callerInfo := "[AuthService.Create]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
+	user.Email = domain.NormalizeEmail(user.Email)
+
 	password, err := security.HashPassword(user.Password)
 	if err != nil {
 		l.Error("error hashing password",
@@ -51,50 +101,866 @@ func (a AuthService) Register(ctx context.Context, user domain.User) (domain.Use
 		return user, err
 	}
 
+	// the account is already created, a failed delivery can be retried through ResendVerification
+	if err = a.sendVerification(ctx, user); err != nil {
+		l.Error("error send verification email",
+			zap.Error(err),
+		)
+	}
+
 	return user, nil
 }
 
-func (a AuthService) GenerateToken(ctx context.Context, user domain.User) (string, error) {
+func (a AuthService) VerifyEmail(ctx context.Context, verificationToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.VerifyEmail]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	userID, email, err := security.ParseVerificationToken(verificationToken)
+	if err != nil {
+		l.Error("error parse verification token", zap.Error(err))
+		return err
+	}
+
+	_, err = a.authRepository.MarkVerified(ctx, userID, email)
+	if err != nil {
+		l.Error("error mark user as verified", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (a AuthService) ResendVerification(ctx context.Context, userID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.ResendVerification]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := a.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return err
+	}
+
+	if user.Verified {
+		err = domain.ErrEmailAlreadyVerified
+		l.Error("email already verified", zap.Error(err))
+		return err
+	}
+
+	err = a.sendVerification(ctx, user)
+	if err != nil {
+		l.Error("error send verification email", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GenerateToken starts a new session for a completed login.
+func (a AuthService) GenerateToken(
+	ctx context.Context,
+	user domain.User,
+	client domain.SessionClient,
+) (domain.AuthToken, error) {
 	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
 	defer cancel()
 
 	callerInfo := "[AuthService.GenerateToken]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	token, err := security.GenerateAccessToken(user)
+	// every login flow ends here, so this also covers two-factor and OpenID Connect logins
+	if user.Suspended {
+		err := domain.ErrUserSuspended
+		l.Error("user suspended", zap.Error(err))
+		return domain.AuthToken{}, err
+	}
+
+	// every fresh login starts a new session, which is also a new refresh token family
+	session := domain.Session{
+		ID:        id.New(),
+		UserID:    user.ID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}
+
+	accessToken, err := security.GenerateAccessToken(user, session.ID)
 	if err != nil {
 		l.Error("error generating token",
 			zap.Error(err),
 		)
-		return "", err
+		return domain.AuthToken{}, err
+	}
+
+	tx, err := a.tokenRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return domain.AuthToken{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	_, tx, err = a.sessionRepository.Create(ctx, session, tx)
+	if err != nil {
+		l.Error("error create session",
+			zap.Error(err),
+		)
+		return domain.AuthToken{}, err
+	}
+
+	refreshToken, tx, err := a.issueRefreshToken(ctx, user.ID, session.ID, tx)
+	if err != nil {
+		l.Error("error generating refresh token",
+			zap.Error(err),
+		)
+		return domain.AuthToken{}, err
+	}
+
+	err = a.tokenRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return domain.AuthToken{}, err
+	}
+
+	token := domain.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
 	}
 
 	return token, nil
 }
 
-func (a AuthService) Login(ctx context.Context, user domain.User) (domain.User, error) {
+func (a AuthService) RefreshToken(ctx context.Context, refreshToken string) (domain.User, domain.AuthToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.RefreshToken]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.tokenRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	storedToken, err := a.tokenRepository.GetByHash(ctx, security.HashToken(refreshToken), tx)
+	if err != nil {
+		l.Error("error get refresh token", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	if !storedToken.RevokedAt.IsZero() {
+		err = domain.ErrInvalidRefreshToken
+		l.Error("refresh token revoked", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	// A used token being presented again means it was leaked,
+	// so every token descending from the same login is revoked.
+	if !storedToken.UsedAt.IsZero() {
+		tx, err = a.tokenRepository.RevokeFamily(ctx, storedToken.FamilyID, tx)
+		if err != nil {
+			l.Error("error revoke refresh token family", zap.Error(err))
+			return domain.User{}, domain.AuthToken{}, err
+		}
+
+		err = a.tokenRepository.TxCommit(ctx, tx)
+		if err != nil {
+			l.Error("error commit transaction", zap.Error(err))
+			return domain.User{}, domain.AuthToken{}, err
+		}
+
+		err = domain.ErrRefreshTokenReused
+		l.Warn("refresh token reused",
+			zap.String("userID", storedToken.UserID.String()),
+			zap.String("familyID", storedToken.FamilyID.String()),
+		)
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	if storedToken.Expired {
+		err = domain.ErrInvalidRefreshToken
+		l.Error("refresh token expired", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	err = a.tokenRepository.MarkUsed(ctx, storedToken.ID, tx)
+	if err != nil {
+		l.Error("error mark refresh token as used", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	user, err := a.authRepository.Get(ctx, storedToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			err = domain.ErrInvalidRefreshToken
+		}
+		l.Error("error get user", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	if user.Suspended {
+		err = domain.ErrUserSuspended
+		l.Error("user suspended", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	accessToken, err := security.GenerateAccessToken(user, storedToken.FamilyID)
+	if err != nil {
+		l.Error("error generating token", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	newRefreshToken, _, err := a.issueRefreshToken(ctx, user.ID, storedToken.FamilyID, tx)
+	if err != nil {
+		l.Error("error generating refresh token", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	err = a.tokenRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	token := domain.AuthToken{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}
+
+	return user, token, nil
+}
+
+func (a AuthService) Logout(ctx context.Context, token domain.AccessToken, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.Logout]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := a.revocationRepository.Revoke(ctx, token)
+	if err != nil {
+		l.Error("error revoke access token", zap.Error(err))
+		return err
+	}
+
+	// tokens issued before sessions were introduced have none
+	if token.SessionID != (ulid.ULID{}) {
+		err = a.revocationRepository.RevokeSession(ctx, token.UserID, token.SessionID)
+		if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+			l.Error("error revoke session", zap.Error(err))
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	err = a.tokenRepository.RevokeFamilyByHash(ctx, token.UserID, security.HashToken(refreshToken))
+	if err != nil {
+		l.Error("error revoke refresh token", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (a AuthService) LogoutAll(ctx context.Context, userID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.LogoutAll]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := a.revokeAll(ctx, userID)
+	if err != nil {
+		l.Error("error revoke tokens", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (a AuthService) ChangePassword(
+	ctx context.Context,
+	userID ulid.ULID,
+	oldPassword, newPassword string,
+	client domain.SessionClient,
+) (domain.User, domain.AuthToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.ChangePassword]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := a.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	// users created through OpenID Connect have no password until they reset it
+	if user.Password == "" {
+		err = domain.ErrPasswordNotSet
+		l.Error("user has no password", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	if err = security.ComparePasswords(user.Password, oldPassword); err != nil {
+		l.Error("error compare password", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, domain.InvalidPassword
+	}
+
+	password, err := security.HashPassword(newPassword)
+	if err != nil {
+		l.Error("error hashing password", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	_, err = a.authRepository.UpdatePassword(ctx, userID, password)
+	if err != nil {
+		l.Error("error update password", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	// sign out every other device, the caller gets a fresh pair of tokens instead
+	err = a.revokeAll(ctx, userID)
+	if err != nil {
+		l.Error("error revoke tokens", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	token, err := a.GenerateToken(ctx, user, client)
+	if err != nil {
+		l.Error("error generate token", zap.Error(err))
+		return domain.User{}, domain.AuthToken{}, err
+	}
+
+	return user, token, nil
+}
+
+func (a AuthService) ForgotPassword(ctx context.Context, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.ForgotPassword]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := a.authRepository.GetByEmail(ctx, domain.NormalizeEmail(email))
+	if err != nil {
+		// unknown emails are not reported, otherwise this endpoint reveals registered accounts
+		if errors.Is(err, domain.UserNotFoundError) {
+			l.Info("password reset requested for unknown email")
+			return nil
+		}
+		l.Error("error get user by email", zap.Error(err))
+		return err
+	}
+
+	token, tokenHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		l.Error("error generating opaque token", zap.Error(err))
+		return err
+	}
+
+	resetToken := domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: security.PasswordResetTokenExpiry(),
+	}
+
+	resetToken, err = a.passwordResetRepository.Create(ctx, resetToken)
+	if err != nil {
+		l.Error("error store reset token", zap.Error(err))
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: passwordResetSubject,
+		Body:    fmt.Sprintf(passwordResetBody, user.Name, token, resetToken.ExpiresAt.Format(time.RFC1123)),
+	}
+
+	err = a.mailer.Send(ctx, msg)
+	if err != nil {
+		l.Error("error send reset email", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (a AuthService) ResetPassword(ctx context.Context, resetToken, newPassword string) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.ResetPassword]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	tx, err := a.passwordResetRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// used and expired tokens are not found
+	storedToken, err := a.passwordResetRepository.GetByHash(ctx, security.HashToken(resetToken), tx)
+	if err != nil {
+		l.Error("error get reset token", zap.Error(err))
+		return err
+	}
+
+	password, err := security.HashPassword(newPassword)
+	if err != nil {
+		l.Error("error hashing password", zap.Error(err))
+		return err
+	}
+
+	user, err := a.authRepository.Get(ctx, storedToken.UserID)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			err = domain.ErrInvalidResetToken
+		}
+		l.Error("error get user", zap.Error(err))
+		return err
+	}
+
+	_, err = a.authRepository.UpdatePassword(ctx, storedToken.UserID, password, tx)
+	if err != nil {
+		if errors.Is(err, domain.UserNotFoundError) {
+			err = domain.ErrInvalidResetToken
+		}
+		l.Error("error update password", zap.Error(err))
+		return err
+	}
+
+	// the reset link was mailed to the address, using it proves the user controls the inbox
+	_, err = a.authRepository.MarkVerified(ctx, user.ID, user.Email, tx)
+	if err != nil {
+		l.Error("error mark user as verified", zap.Error(err))
+		return err
+	}
+
+	err = a.passwordResetRepository.MarkUsed(ctx, storedToken.ID, tx)
+	if err != nil {
+		l.Error("error mark reset token as used", zap.Error(err))
+		return err
+	}
+
+	err = a.passwordResetRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	err = a.revokeAll(ctx, storedToken.UserID)
+	if err != nil {
+		l.Error("error revoke tokens", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (a AuthService) sendVerification(ctx context.Context, user domain.User) error {
+	token, err := security.GenerateVerificationToken(user)
+	if err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: verificationSubject,
+		Body:    fmt.Sprintf(verificationBody, user.Name, token),
+	}
+
+	return a.mailer.Send(ctx, msg)
+}
+
+// ListSessions returns the devices the user is signed in on, most recently used first.
+func (a AuthService) ListSessions(ctx context.Context, userID ulid.ULID) ([]domain.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.ListSessions]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	sessions, err := a.sessionRepository.List(ctx, userID)
+	if err != nil {
+		l.Error("error get sessions", zap.Error(err))
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession signs a device out, its access tokens are rejected
+// and its refresh tokens can't be used anymore.
+func (a AuthService) RevokeSession(ctx context.Context, userID, sessionID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.RevokeSession]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := a.revocationRepository.RevokeSession(ctx, userID, sessionID)
+	if err != nil {
+		l.Error("error revoke session", zap.Error(err))
+		return err
+	}
+
+	_, err = a.tokenRepository.RevokeFamily(ctx, sessionID)
+	if err != nil {
+		l.Error("error revoke refresh token family", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// revokeAll signs the user out of every device by revoking
+// all refresh tokens and every access token issued so far.
+func (a AuthService) revokeAll(ctx context.Context, userID ulid.ULID) error {
+	_, err := a.tokenRepository.RevokeAllByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return a.revocationRepository.RevokeAll(ctx, userID)
+}
+
+func (a AuthService) issueRefreshToken(
+	ctx context.Context,
+	userID, familyID ulid.ULID,
+	txs ...pgx.Tx,
+) (string, pgx.Tx, error) {
+	callerInfo := "[AuthService.issueRefreshToken]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	token, tokenHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		l.Error("error generating opaque token", zap.Error(err))
+		return "", nil, err
+	}
+
+	refreshToken := domain.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: tokenHash,
+		ExpiresAt: security.RefreshTokenExpiry(),
+	}
+
+	_, tx, err := a.tokenRepository.Create(ctx, refreshToken, txs...)
+	if err != nil {
+		l.Error("error store refresh token", zap.Error(err))
+		return "", tx, err
+	}
+
+	return token, tx, nil
+}
+
+func (a AuthService) Login(ctx context.Context, user domain.User, clientIP string) (domain.User, error) {
 	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
 	defer cancel()
 
 	callerInfo := "[AuthService.Login]"
 	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
 
-	userData, err := a.authRepository.GetByEmail(ctx, user.Email)
+	user.Email = domain.NormalizeEmail(user.Email)
+	emailKey, ipKey := domain.LoginEmailKey(user.Email), domain.LoginIPKey(clientIP)
+
+	lockedUntil, err := a.loginAttemptRepository.LockedUntil(ctx, emailKey, ipKey)
 	if err != nil {
-		l.Error("error get user by email",
+		l.Error("error get login lockout",
+			zap.Error(err),
+		)
+		return domain.User{}, err
+	}
+	if retryAfter := time.Until(lockedUntil); retryAfter > 0 {
+		err = domain.LoginLockedError{RetryAfter: retryAfter}
+		l.Error("login locked",
 			zap.Error(err),
 		)
 		return domain.User{}, err
 	}
 
+	userData, err := a.authRepository.GetByEmail(ctx, user.Email)
+	if err != nil {
+		if !errors.Is(err, domain.UserNotFoundError) {
+			l.Error("error get user by email",
+				zap.Error(err),
+			)
+			return domain.User{}, err
+		}
+		l.Error("user not found",
+			zap.Error(err),
+		)
+		security.CompareDummyPassword(user.Password)
+		return domain.User{}, a.loginFailed(ctx, domain.ErrInvalidCredentials, emailKey, ipKey)
+	}
+
+	// users created through OpenID Connect have no password until they reset it
+	if userData.Password == "" {
+		l.Error("user has no password")
+		security.CompareDummyPassword(user.Password)
+		return domain.User{}, a.loginFailed(ctx, domain.ErrInvalidCredentials, emailKey, ipKey)
+	}
+
 	if err = security.ComparePasswords(userData.Password, user.Password); err != nil {
 		l.Error("error compare password",
 			zap.Error(err),
 		)
-		return domain.User{}, domain.InvalidPassword
+		return domain.User{}, a.loginFailed(ctx, domain.ErrInvalidCredentials, emailKey, ipKey)
+	}
+
+	// checked after the password, so the response doesn't tell whether an email belongs to a suspended user
+	if userData.Suspended {
+		err = domain.ErrUserSuspended
+		l.Error("user suspended",
+			zap.Error(err),
+		)
+		return domain.User{}, err
+	}
+
+	if security.NeedsRehash(userData.Password) {
+		a.rehashPassword(ctx, userData.ID, user.Password)
+	}
+
+	// only the email is reset, otherwise one valid account would unlock a brute forcing IP
+	err = a.loginAttemptRepository.Reset(ctx, emailKey, time.Now().Add(-a.loginThrottle.Window))
+	if err != nil {
+		l.Error("error reset login attempts",
+			zap.Error(err),
+		)
 	}
 
 	return userData, nil
 }
 
+// IssueMFAChallenge is called after a successful password check for users with two-factor
+// authentication, the returned token is exchanged for an access token in LoginMFA.
+func (a AuthService) IssueMFAChallenge(ctx context.Context, user domain.User) (string, error) {
+	callerInfo := "[AuthService.IssueMFAChallenge]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	mfaToken, err := security.GenerateMFAPendingToken(user)
+	if err != nil {
+		l.Error("error generating mfa pending token", zap.Error(err))
+		return "", err
+	}
+
+	return mfaToken, nil
+}
+
+// LoginMFA completes a login started by Login with a TOTP code or an unused recovery code.
+func (a AuthService) LoginMFA(ctx context.Context, mfaToken, code, clientIP string) (domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.LoginMFA]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	userID, err := security.ParseMFAPendingToken(mfaToken)
+	if err != nil {
+		l.Error("error parse mfa pending token", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	mfaKey, ipKey := domain.LoginMFAKey(userID), domain.LoginIPKey(clientIP)
+
+	lockedUntil, err := a.loginAttemptRepository.LockedUntil(ctx, mfaKey, ipKey)
+	if err != nil {
+		l.Error("error get login lockout", zap.Error(err))
+		return domain.User{}, err
+	}
+	if retryAfter := time.Until(lockedUntil); retryAfter > 0 {
+		err = domain.LoginLockedError{RetryAfter: retryAfter}
+		l.Error("login locked", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	mfa, err := a.mfaRepository.Get(ctx, userID)
+	if err != nil || !mfa.Enabled {
+		// two-factor authentication was turned off since the token was issued
+		if err == nil || errors.Is(err, domain.ErrMFANotSetUp) {
+			err = domain.ErrInvalidMFAToken
+		}
+		l.Error("error get mfa", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	if step, ok := security.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		err = a.mfaRepository.UseStep(ctx, userID, step)
+	} else {
+		err = a.mfaRepository.UseRecoveryCode(ctx, userID, security.HashRecoveryCode(code))
+	}
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			l.Error("invalid mfa code", zap.Error(err))
+			return domain.User{}, a.loginFailed(ctx, err, mfaKey, ipKey)
+		}
+		l.Error("error verify mfa code", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	err = a.loginAttemptRepository.Reset(ctx, mfaKey, time.Now().Add(-a.loginThrottle.Window))
+	if err != nil {
+		l.Error("error reset login attempts", zap.Error(err))
+	}
+
+	user, err := a.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// SetupMFA creates a new TOTP secret, it only takes effect once confirmed through EnableMFA.
+func (a AuthService) SetupMFA(ctx context.Context, userID ulid.ULID) (domain.MFASetup, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.SetupMFA]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := a.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.MFASetup{}, err
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		l.Error("error generating totp secret", zap.Error(err))
+		return domain.MFASetup{}, err
+	}
+
+	err = a.mfaRepository.Setup(ctx, userID, secret)
+	if err != nil {
+		l.Error("error store totp secret", zap.Error(err))
+		return domain.MFASetup{}, err
+	}
+
+	setup := domain.MFASetup{
+		Secret: secret,
+		URI:    security.TOTPURI(configs.Runtime.App.Name, user.Email, secret),
+	}
+
+	return setup, nil
+}
+
+// EnableMFA confirms the secret from SetupMFA with a code from the authenticator app
+// and returns the recovery codes, they are only stored hashed and can't be shown again.
+func (a AuthService) EnableMFA(ctx context.Context, userID ulid.ULID, code string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, a.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[AuthService.EnableMFA]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	mfa, err := a.mfaRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get mfa", zap.Error(err))
+		return nil, err
+	}
+
+	if mfa.Enabled {
+		err = domain.ErrMFAAlreadyEnabled
+		l.Error("mfa already enabled", zap.Error(err))
+		return nil, err
+	}
+
+	step, ok := security.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		err = domain.ErrInvalidMFACode
+		l.Error("invalid mfa code", zap.Error(err))
+		return nil, err
+	}
+
+	recoveryCodes, err := security.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		l.Error("error generating recovery codes", zap.Error(err))
+		return nil, err
+	}
+
+	codeHashes := make([]string, len(recoveryCodes))
+	for i, recoveryCode := range recoveryCodes {
+		codeHashes[i] = security.HashRecoveryCode(recoveryCode)
+	}
+
+	err = a.mfaRepository.Enable(ctx, userID, step, codeHashes)
+	if err != nil {
+		l.Error("error enable mfa", zap.Error(err))
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// rehashPassword upgrades a hash created with an outdated algorithm or parameters,
+// the plain password is only available while logging in. Failures keep the old hash.
+func (a AuthService) rehashPassword(ctx context.Context, userID ulid.ULID, password string) {
+	callerInfo := "[AuthService.rehashPassword]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	hashedPassword, err := security.HashPassword(password)
+	if err != nil {
+		l.Error("error hashing password", zap.Error(err))
+		return
+	}
+
+	_, err = a.authRepository.UpdatePassword(ctx, userID, hashedPassword)
+	if err != nil {
+		l.Error("error update password", zap.Error(err))
+		return
+	}
+
+	l.Info("password rehashed")
+}
+
+// loginFailed records a failed login for every key, locks the keys that crossed the threshold
+// and returns failure, the error reported to the client.
+func (a AuthService) loginFailed(ctx context.Context, failure error, keys ...string) error {
+	callerInfo := "[AuthService.loginFailed]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	now := time.Now()
+	windowStart := now.Add(-a.loginThrottle.Window)
+
+	for _, key := range keys {
+		failures, err := a.loginAttemptRepository.RecordFailure(ctx, key, windowStart)
+		if err != nil {
+			l.Error("error record login failure", zap.Error(err))
+			return err
+		}
+
+		lockout := a.loginThrottle.Lockout(failures)
+		if lockout <= 0 {
+			continue
+		}
+
+		err = a.loginAttemptRepository.Lock(ctx, key, now.Add(lockout))
+		if err != nil {
+			l.Error("error lock login", zap.Error(err))
+			return err
+		}
+	}
+
+	return failure
+}
+
 var _ AuthServiceContract = (*AuthService)(nil)
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/common/oidc"
+	"cats-social/common/security"
+	"cats-social/internal/application/user/repository"
+	"cats-social/internal/domain"
+)
+
+const maxNameLength = 50
+
+type OIDCService struct {
+	authRepository repository.AuthRepositoryContract
+	oidcRepository repository.OIDCRepositoryContract
+	provider       *oidc.Provider
+	stateExpire    time.Duration
+	contextTimeout time.Duration
+}
+
+func NewOIDCService(
+	timeout time.Duration,
+	authRepository repository.AuthRepositoryContract,
+	oidcRepository repository.OIDCRepositoryContract,
+	provider *oidc.Provider,
+	stateExpire time.Duration,
+) *OIDCService {
+	oidcService := &OIDCService{
+		authRepository: authRepository,
+		oidcRepository: oidcRepository,
+		provider:       provider,
+		stateExpire:    stateExpire,
+		contextTimeout: timeout,
+	}
+
+	return oidcService
+}
+
+// AuthorizationURL starts a login at the identity provider. The state, nonce and PKCE
+// code verifier are kept server side until the identity provider redirects back.
+func (o OIDCService) AuthorizationURL(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[OIDCService.AuthorizationURL]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	if !o.provider.Enabled() {
+		err := domain.ErrOIDCNotConfigured
+		l.Error("openid connect disabled", zap.Error(err))
+		return "", err
+	}
+
+	state, stateHash, err := security.GenerateOpaqueToken()
+	if err != nil {
+		l.Error("error generating state", zap.Error(err))
+		return "", err
+	}
+
+	nonce, err := oidc.NewNonce()
+	if err != nil {
+		l.Error("error generating nonce", zap.Error(err))
+		return "", err
+	}
+
+	codeVerifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		l.Error("error generating code verifier", zap.Error(err))
+		return "", err
+	}
+
+	err = o.oidcRepository.CreateState(ctx, domain.OIDCLoginState{
+		StateHash:    stateHash,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(o.stateExpire),
+	})
+	if err != nil {
+		l.Error("error store login state", zap.Error(err))
+		return "", err
+	}
+
+	authURL, err := o.provider.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(codeVerifier))
+	if err != nil {
+		l.Error("error build authorization url", zap.Error(err))
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// Callback finishes a login started by AuthorizationURL. The identity is matched by issuer and
+// subject first, then linked to the user with the same verified email, or a new user is created.
+func (o OIDCService) Callback(ctx context.Context, code, state string) (domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[OIDCService.Callback]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	if !o.provider.Enabled() {
+		err := domain.ErrOIDCNotConfigured
+		l.Error("openid connect disabled", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	loginState, err := o.oidcRepository.ConsumeState(ctx, security.HashToken(state))
+	if err != nil {
+		l.Error("error consume login state", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	claims, err := o.provider.Exchange(ctx, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		l.Error("error exchange authorization code", zap.Error(err))
+		if errors.Is(err, oidc.ErrExchangeFailed) || errors.Is(err, oidc.ErrInvalidIDToken) {
+			return domain.User{}, domain.ErrOIDCLoginFailed
+		}
+		return domain.User{}, err
+	}
+
+	claims.Email = domain.NormalizeEmail(claims.Email)
+	if claims.Email == "" || !claims.EmailVerified {
+		err = domain.ErrOIDCEmailNotVerified
+		l.Error("email not verified by identity provider", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	identity, err := o.oidcRepository.GetIdentity(ctx, o.provider.Issuer(), claims.Subject)
+	if err == nil {
+		return o.getUser(ctx, identity)
+	}
+	if !errors.Is(err, domain.ErrIdentityNotFound) {
+		l.Error("error get identity", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	user, err := o.findOrCreateUser(ctx, claims)
+	if err != nil {
+		l.Error("error find or create user", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	identity, err = o.oidcRepository.LinkIdentity(ctx, domain.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  o.provider.Issuer(),
+		Subject: claims.Subject,
+		Email:   claims.Email,
+	})
+	if err != nil {
+		l.Error("error link identity", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	return o.getUser(ctx, identity)
+}
+
+func (o OIDCService) getUser(ctx context.Context, identity domain.UserIdentity) (domain.User, error) {
+	callerInfo := "[OIDCService.getUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := o.authRepository.Get(ctx, identity.UserID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// findOrCreateUser returns the user owning the verified email. Only users who verified the
+// email themselves are linked, an unverified account may have been registered by someone else
+// with a password they chose. New users get no password, they can set one through the password reset flow.
+func (o OIDCService) findOrCreateUser(ctx context.Context, claims oidc.Claims) (domain.User, error) {
+	callerInfo := "[OIDCService.findOrCreateUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := o.authRepository.GetByEmail(ctx, claims.Email)
+	if err == nil {
+		if !user.Verified {
+			err = domain.ErrOIDCAccountNotVerified
+			l.Error("refusing to link unverified user", zap.Error(err))
+			return domain.User{}, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domain.UserNotFoundError) {
+		l.Error("error get user by email", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	user, err = o.authRepository.Create(ctx, domain.User{
+		Email:    claims.Email,
+		Name:     displayName(claims),
+		Verified: true,
+	})
+	if err != nil {
+		l.Error("error create user", zap.Error(err))
+		return domain.User{}, err
+	}
+
+	return user, nil
+}
+
+// displayName falls back to the local part of the email when the identity provider has no name.
+func displayName(claims oidc.Claims) string {
+	name := strings.TrimSpace(claims.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	if runes := []rune(name); len(runes) > maxNameLength {
+		name = string(runes[:maxNameLength])
+	}
+
+	return name
+}
+
+var _ OIDCServiceContract = (*OIDCService)(nil)
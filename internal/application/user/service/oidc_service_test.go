@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/oklog/ulid/v2"
+
+	"cats-social/common/oidc"
+	"cats-social/common/oidc/oidctest"
+	"cats-social/internal/domain"
+)
+
+const testClientID = "cats-social"
+
+// fakeAuthRepository keeps users in memory, only the methods the OIDC login uses do anything.
+type fakeAuthRepository struct {
+	users map[ulid.ULID]domain.User
+}
+
+func (f *fakeAuthRepository) Create(_ context.Context, user domain.User) (domain.User, error) {
+	user.ID = ulid.Make()
+	user.CreatedAt = time.Now()
+	f.users[user.ID] = user
+	return user, nil
+}
+
+func (f *fakeAuthRepository) GetByEmail(_ context.Context, email string) (domain.User, error) {
+	for _, user := range f.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return domain.User{}, domain.UserNotFoundError
+}
+
+func (f *fakeAuthRepository) Get(_ context.Context, userID ulid.ULID) (domain.User, error) {
+	user, ok := f.users[userID]
+	if !ok {
+		return domain.User{}, domain.UserNotFoundError
+	}
+	return user, nil
+}
+
+func (f *fakeAuthRepository) MarkVerified(_ context.Context, userID ulid.ULID, _ string, _ ...pgx.Tx) (pgx.Tx, error) {
+	user := f.users[userID]
+	user.Verified = true
+	f.users[userID] = user
+	return nil, nil
+}
+
+func (f *fakeAuthRepository) List(context.Context, domain.UserQueryParam) ([]domain.User, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthRepository) Update(_ context.Context, user domain.User) (domain.User, error) {
+	return user, nil
+}
+
+func (f *fakeAuthRepository) UpdatePassword(context.Context, ulid.ULID, string, ...pgx.Tx) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthRepository) Suspend(context.Context, ulid.ULID, ...pgx.Tx) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthRepository) Delete(context.Context, ulid.ULID, ...pgx.Tx) (pgx.Tx, error) {
+	return nil, nil
+}
+
+func (f *fakeAuthRepository) TxBegin(context.Context) (pgx.Tx, error) { return nil, nil }
+
+func (f *fakeAuthRepository) TxCommit(context.Context, pgx.Tx) error { return nil }
+
+type fakeOIDCRepository struct {
+	states     map[string]domain.OIDCLoginState
+	identities []domain.UserIdentity
+}
+
+func (f *fakeOIDCRepository) CreateState(_ context.Context, state domain.OIDCLoginState) error {
+	f.states[state.StateHash] = state
+	return nil
+}
+
+func (f *fakeOIDCRepository) ConsumeState(_ context.Context, stateHash string) (domain.OIDCLoginState, error) {
+	state, ok := f.states[stateHash]
+	if !ok {
+		return domain.OIDCLoginState{}, domain.ErrInvalidOIDCState
+	}
+	delete(f.states, stateHash)
+	return state, nil
+}
+
+func (f *fakeOIDCRepository) GetIdentity(_ context.Context, issuer, subject string) (domain.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return domain.UserIdentity{}, domain.ErrIdentityNotFound
+}
+
+func (f *fakeOIDCRepository) LinkIdentity(_ context.Context, identity domain.UserIdentity) (domain.UserIdentity, error) {
+	identity.ID = ulid.Make()
+	f.identities = append(f.identities, identity)
+	return identity, nil
+}
+
+type oidcTestEnv struct {
+	service *OIDCService
+	idp     *oidctest.Server
+	users   *fakeAuthRepository
+	oidc    *fakeOIDCRepository
+}
+
+func newOIDCTestEnv(t *testing.T) oidcTestEnv {
+	t.Helper()
+
+	idp := oidctest.NewServer(t, testClientID)
+	provider := oidc.NewProvider(oidc.Config{
+		Issuer:      idp.Issuer(),
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/v1/user/oidc/callback",
+		Scopes:      []string{"openid", "email"},
+	}, idp.Client())
+
+	users := &fakeAuthRepository{users: make(map[ulid.ULID]domain.User)}
+	oidcRepository := &fakeOIDCRepository{states: make(map[string]domain.OIDCLoginState)}
+
+	return oidcTestEnv{
+		service: NewOIDCService(5*time.Second, users, oidcRepository, provider, time.Minute),
+		idp:     idp,
+		users:   users,
+		oidc:    oidcRepository,
+	}
+}
+
+// login runs the whole flow, the identity provider signs in the user with the given email.
+func (e oidcTestEnv) login(t *testing.T, subject, email string) (domain.User, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	authURL, err := e.service.AuthorizationURL(ctx)
+	if err != nil {
+		t.Fatalf("AuthorizationURL: %v", err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization url: %v", err)
+	}
+	query := parsed.Query()
+
+	code := e.idp.IssueCode(t, query.Get("code_challenge"), e.idp.Claims(query.Get("nonce"), subject, email))
+
+	return e.service.Callback(ctx, code, query.Get("state"))
+}
+
+func TestCallbackCreatesUser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	user, err := env.login(t, "subject-1", "New.User@Example.com")
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+
+	if user.Email != "new.user@example.com" || !user.Verified || user.Password != "" {
+		t.Fatalf("created user = %+v", user)
+	}
+	if len(env.users.users) != 1 {
+		t.Fatalf("users = %d, want 1", len(env.users.users))
+	}
+	if len(env.oidc.identities) != 1 || env.oidc.identities[0].UserID != user.ID {
+		t.Fatalf("identities = %+v", env.oidc.identities)
+	}
+
+	// the next login finds the user through the linked identity
+	again, err := env.login(t, "subject-1", "new.user@example.com")
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if again.ID != user.ID || len(env.oidc.identities) != 1 {
+		t.Fatalf("second login user = %+v, identities = %d", again, len(env.oidc.identities))
+	}
+}
+
+func TestCallbackLinksVerifiedUserByEmail(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	existing, _ := env.users.Create(context.Background(), domain.User{
+		Email:    "owner@example.com",
+		Name:     "Owner",
+		Password: "hash",
+		Verified: true,
+	})
+
+	user, err := env.login(t, "subject-1", "owner@example.com")
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+
+	if user.ID != existing.ID {
+		t.Fatalf("linked user = %s, want %s", user.ID, existing.ID)
+	}
+	if len(env.users.users) != 1 {
+		t.Fatalf("users = %d, want 1", len(env.users.users))
+	}
+	if len(env.oidc.identities) != 1 || env.oidc.identities[0].UserID != existing.ID {
+		t.Fatalf("identities = %+v", env.oidc.identities)
+	}
+}
+
+func TestCallbackRefusesUnverifiedUser(t *testing.T) {
+	env := newOIDCTestEnv(t)
+
+	existing, _ := env.users.Create(context.Background(), domain.User{
+		Email:    "victim@example.com",
+		Name:     "Squatter",
+		Password: "hash chosen by someone else",
+	})
+
+	_, err := env.login(t, "subject-1", "victim@example.com")
+	if !errors.Is(err, domain.ErrOIDCAccountNotVerified) {
+		t.Fatalf("Callback error = %v, want %v", err, domain.ErrOIDCAccountNotVerified)
+	}
+
+	if env.users.users[existing.ID].Verified {
+		t.Fatal("unverified user was marked as verified")
+	}
+	if len(env.oidc.identities) != 0 {
+		t.Fatalf("identities = %+v, want none", env.oidc.identities)
+	}
+}
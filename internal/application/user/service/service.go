@@ -3,11 +3,47 @@ package service
 import (
 	"context"
 
+	"github.com/oklog/ulid/v2"
+
 	"cats-social/internal/domain"
 )
 
 type AuthServiceContract interface {
 	Register(ctx context.Context, user domain.User) (domain.User, error)
-	GenerateToken(ctx context.Context, user domain.User) (string, error)
-	Login(ctx context.Context, user domain.User) (domain.User, error)
+	GenerateToken(ctx context.Context, user domain.User, client domain.SessionClient) (domain.AuthToken, error)
+	Login(ctx context.Context, user domain.User, clientIP string) (domain.User, error)
+	IssueMFAChallenge(ctx context.Context, user domain.User) (string, error)
+	LoginMFA(ctx context.Context, mfaToken, code, clientIP string) (domain.User, error)
+	SetupMFA(ctx context.Context, userID ulid.ULID) (domain.MFASetup, error)
+	EnableMFA(ctx context.Context, userID ulid.ULID, code string) ([]string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (domain.User, domain.AuthToken, error)
+	Logout(ctx context.Context, token domain.AccessToken, refreshToken string) error
+	LogoutAll(ctx context.Context, userID ulid.ULID) error
+	ListSessions(ctx context.Context, userID ulid.ULID) ([]domain.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID ulid.ULID) error
+	ChangePassword(
+		ctx context.Context,
+		userID ulid.ULID,
+		oldPassword, newPassword string,
+		client domain.SessionClient,
+	) (domain.User, domain.AuthToken, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, resetToken, newPassword string) error
+	VerifyEmail(ctx context.Context, verificationToken string) error
+	ResendVerification(ctx context.Context, userID ulid.ULID) error
+}
+
+type OIDCServiceContract interface {
+	AuthorizationURL(ctx context.Context) (string, error)
+	Callback(ctx context.Context, code, state string) (domain.User, error)
+}
+
+type UserServiceContract interface {
+	GetProfile(ctx context.Context, userID ulid.ULID) (domain.UserProfile, error)
+	GetOwnerProfile(ctx context.Context, viewerID, ownerID ulid.ULID) (domain.OwnerProfile, error)
+	UpdateProfile(ctx context.Context, user domain.User) (domain.UserProfile, error)
+	DeleteAccount(ctx context.Context, userID ulid.ULID, password string) error
+	BlockUser(ctx context.Context, userID, blockedID ulid.ULID) error
+	UnblockUser(ctx context.Context, userID, blockedID ulid.ULID) error
+	ListBlocks(ctx context.Context, userID ulid.ULID) ([]domain.Block, error)
 }
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"go.uber.org/zap"
+
+	"cats-social/common/logger"
+	"cats-social/common/security"
+	catRepo "cats-social/internal/application/cat/repository"
+	matchRepo "cats-social/internal/application/match/repository"
+	"cats-social/internal/application/user/repository"
+	"cats-social/internal/domain"
+)
+
+type UserService struct {
+	authRepository       repository.AuthRepositoryContract
+	tokenRepository      repository.TokenRepositoryContract
+	revocationRepository repository.RevocationRepositoryContract
+	blockRepository      repository.BlockRepositoryContract
+	catRepository        catRepo.CatRepositoryContract
+	matchRepository      matchRepo.MatchRepositoryContract
+	contextTimeout       time.Duration
+}
+
+func NewUserService(
+	timeout time.Duration,
+	authRepository repository.AuthRepositoryContract,
+	tokenRepository repository.TokenRepositoryContract,
+	revocationRepository repository.RevocationRepositoryContract,
+	blockRepository repository.BlockRepositoryContract,
+	catRepository catRepo.CatRepositoryContract,
+	matchRepository matchRepo.MatchRepositoryContract,
+) *UserService {
+	userService := &UserService{
+		authRepository:       authRepository,
+		tokenRepository:      tokenRepository,
+		revocationRepository: revocationRepository,
+		blockRepository:      blockRepository,
+		catRepository:        catRepository,
+		matchRepository:      matchRepository,
+		contextTimeout:       timeout,
+	}
+
+	return userService
+}
+
+func (u UserService) GetProfile(ctx context.Context, userID ulid.ULID) (domain.UserProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.GetProfile]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := u.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.UserProfile{}, err
+	}
+
+	catCount, err := u.catRepository.Count(ctx, userID, domain.QueryParam{
+		Owned: domain.TrueBool,
+	})
+	if err != nil {
+		l.Error("error count cats", zap.Error(err))
+		return domain.UserProfile{}, err
+	}
+
+	matchCount, err := u.matchRepository.Count(ctx, userID)
+	if err != nil {
+		l.Error("error count matches", zap.Error(err))
+		return domain.UserProfile{}, err
+	}
+
+	profile := domain.UserProfile{
+		User:       user,
+		CatCount:   catCount,
+		MatchCount: matchCount,
+	}
+
+	return profile, nil
+}
+
+// GetOwnerProfile returns the public profile of a cat owner with the cats they still own.
+// The email is only visible to the owner and to users sharing an approved match with them.
+func (u UserService) GetOwnerProfile(ctx context.Context, viewerID, ownerID ulid.ULID) (domain.OwnerProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.GetOwnerProfile]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	owner, err := u.authRepository.Get(ctx, ownerID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return domain.OwnerProfile{}, err
+	}
+
+	// users in a block don't see each other
+	blocked, err := u.blockRepository.IsBlocked(ctx, viewerID, owner.ID)
+	if err != nil {
+		l.Error("error check block", zap.Error(err))
+		return domain.OwnerProfile{}, err
+	}
+
+	if blocked {
+		err = domain.UserNotFoundError
+		l.Error("error check block", zap.Error(err))
+		return domain.OwnerProfile{}, err
+	}
+
+	cats, err := u.catRepository.Get(ctx, owner.ID, domain.QueryParam{
+		Owned: domain.TrueBool,
+	}, true)
+	if err != nil {
+		l.Error("error get cats", zap.Error(err))
+		return domain.OwnerProfile{}, err
+	}
+
+	emailVisible := viewerID == owner.ID
+	if !emailVisible {
+		emailVisible, err = u.matchRepository.HasApprovedMatch(ctx, viewerID, owner.ID)
+		if err != nil {
+			l.Error("error check approved match", zap.Error(err))
+			return domain.OwnerProfile{}, err
+		}
+	}
+
+	profile := domain.OwnerProfile{
+		User:         owner,
+		Cats:         cats,
+		EmailVisible: emailVisible,
+	}
+
+	return profile, nil
+}
+
+func (u UserService) UpdateProfile(ctx context.Context, user domain.User) (domain.UserProfile, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.UpdateProfile]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	_, err := u.authRepository.Update(ctx, user)
+	if err != nil {
+		l.Error("error update user", zap.Error(err))
+		return domain.UserProfile{}, err
+	}
+
+	return u.GetProfile(ctx, user.ID)
+}
+
+func (u UserService) DeleteAccount(ctx context.Context, userID ulid.ULID, password string) error {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.DeleteAccount]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	user, err := u.authRepository.Get(ctx, userID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return err
+	}
+
+	// users created through OpenID Connect have no password until they reset it
+	if user.Password == "" {
+		err = domain.ErrPasswordNotSet
+		l.Error("user has no password", zap.Error(err))
+		return err
+	}
+
+	if err = security.ComparePasswords(user.Password, password); err != nil {
+		l.Error("error compare password", zap.Error(err))
+		return domain.InvalidPassword
+	}
+
+	tx, err := u.authRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	// pending matches are resolved while the cats they point to are still live
+	tx, err = u.matchRepository.DeletePendingByUser(ctx, userID, tx)
+	if err != nil {
+		l.Error("error delete pending matches", zap.Error(err))
+		return err
+	}
+
+	tx, err = u.catRepository.DeleteByUser(ctx, userID, tx)
+	if err != nil {
+		l.Error("error delete cats", zap.Error(err))
+		return err
+	}
+
+	tx, err = u.tokenRepository.RevokeAllByUser(ctx, userID, tx)
+	if err != nil {
+		l.Error("error revoke refresh tokens", zap.Error(err))
+		return err
+	}
+
+	tx, err = u.authRepository.Delete(ctx, userID, tx)
+	if err != nil {
+		l.Error("error delete user", zap.Error(err))
+		return err
+	}
+
+	err = u.authRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	err = u.revocationRepository.RevokeAll(ctx, userID)
+	if err != nil {
+		l.Error("error revoke access tokens", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// BlockUser blocks another user and withdraws the pending match requests between them,
+// approved matches are kept as history.
+func (u UserService) BlockUser(ctx context.Context, userID, blockedID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.BlockUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	if userID == blockedID {
+		err := domain.ErrCannotBlockSelf
+		l.Error("error block user", zap.Error(err))
+		return err
+	}
+
+	_, err := u.authRepository.Get(ctx, blockedID)
+	if err != nil {
+		l.Error("error get user", zap.Error(err))
+		return err
+	}
+
+	tx, err := u.authRepository.TxBegin(ctx)
+	if err != nil {
+		l.Error("error begin transaction", zap.Error(err))
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	tx, err = u.blockRepository.Create(ctx, userID, blockedID, tx)
+	if err != nil {
+		l.Error("error create block", zap.Error(err))
+		return err
+	}
+
+	tx, err = u.matchRepository.DeletePendingBetween(ctx, userID, blockedID, tx)
+	if err != nil {
+		l.Error("error delete pending matches", zap.Error(err))
+		return err
+	}
+
+	err = u.authRepository.TxCommit(ctx, tx)
+	if err != nil {
+		l.Error("error commit transaction", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (u UserService) UnblockUser(ctx context.Context, userID, blockedID ulid.ULID) error {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.UnblockUser]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	err := u.blockRepository.Delete(ctx, userID, blockedID)
+	if err != nil {
+		l.Error("error delete block", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+func (u UserService) ListBlocks(ctx context.Context, userID ulid.ULID) ([]domain.Block, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.contextTimeout)
+	defer cancel()
+
+	callerInfo := "[UserService.ListBlocks]"
+	l := logger.FromCtx(ctx).With(zap.String("caller", callerInfo))
+
+	blocks, err := u.blockRepository.List(ctx, userID)
+	if err != nil {
+		l.Error("error get blocks", zap.Error(err))
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+var _ UserServiceContract = (*UserService)(nil)
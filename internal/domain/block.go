@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrCannotBlockSelf = errors.New("you can't block yourself")
+	ErrBlockNotFound   = errors.New("user is not blocked")
+	ErrUserBlocked     = errors.New("you can't match with a user you blocked or who blocked you")
+)
+
+// Block is a user hiding another user, the block applies in both directions.
+type Block struct {
+	BlockerID ulid.ULID
+	Blocked   User
+	CreatedAt time.Time
+}
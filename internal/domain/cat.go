@@ -79,4 +79,53 @@ type Cat struct {
 	HasMatched  bool
 	ImageUrls   []string
 	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	// Snippet is the description with the search terms highlighted, only set when asked for
+	Snippet string
+}
+
+// CatPatch holds the fields of a partial update, nil fields are left unchanged.
+type CatPatch struct {
+	Name        *string
+	Race        *CatRace
+	Sex         *CatSex
+	AgeInMonth  *int
+	Description *string
+	ImageUrls   []string
+}
+
+func (p CatPatch) IsEmpty() bool {
+	return p.Name == nil && p.Race == nil && p.Sex == nil && p.AgeInMonth == nil &&
+		p.Description == nil && p.ImageUrls == nil
+}
+
+// Apply copies the fields present in the patch to the cat.
+func (p CatPatch) Apply(c *Cat) {
+	if p.Name != nil {
+		c.Name = *p.Name
+	}
+	if p.Race != nil {
+		c.Race = *p.Race
+	}
+	if p.Sex != nil {
+		c.Sex = *p.Sex
+	}
+	if p.AgeInMonth != nil {
+		c.AgeInMonth = *p.AgeInMonth
+	}
+	if p.Description != nil {
+		c.Description = *p.Description
+	}
+	if p.ImageUrls != nil {
+		c.ImageUrls = p.ImageUrls
+	}
+}
+
+// CatDetail is a single cat with its owner and the match requests waiting for an answer,
+// incoming requests were sent to the cat, outgoing ones were sent by it.
+type CatDetail struct {
+	Cat
+	Owner           User
+	PendingIncoming int
+	PendingOutgoing int
 }
@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursorTimeLayout keeps microseconds, the precision postgres stores.
+const cursorTimeLayout = "2006-01-02T15:04:05.000000"
+
+// Cursor points at the last row of a page, the next page starts right after it.
+// Values holds the sort key values of that row, in the order of Sort.
+type Cursor struct {
+	Sort   Sort
+	Values []any
+	ID     ulid.ULID
+}
+
+// NewCatCursor returns a cursor pointing at the cat within a listing ordered by sort.
+func NewCatCursor(sort Sort, cat Cat) Cursor {
+	values := make([]any, len(sort))
+	for i, key := range sort {
+		values[i] = key.Field.value(cat)
+	}
+
+	return Cursor{Sort: sort, Values: values, ID: cat.ID}
+}
+
+func (c Cursor) IsZero() bool {
+	return len(c.Sort) == 0 && c.ID == ulid.ULID{}
+}
+
+type cursorPayload struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	ID     string   `json:"i"`
+}
+
+// Encode returns the cursor as an opaque string safe to use in a query string.
+func (c Cursor) Encode() string {
+	payload := cursorPayload{
+		Sort:   c.Sort.String(),
+		Values: make([]string, len(c.Values)),
+		ID:     c.ID.String(),
+	}
+	for i, value := range c.Values {
+		switch v := value.(type) {
+		case time.Time:
+			payload.Values[i] = v.Format(cursorTimeLayout)
+		default:
+			payload.Values[i] = fmt.Sprint(v)
+		}
+	}
+
+	buf, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	var payload cursorPayload
+	if err = json.Unmarshal(buf, &payload); err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	sort, err := ParseSort(payload.Sort)
+	if err != nil || len(payload.Values) != len(sort) {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	id, err := ulid.Parse(payload.ID)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	values := make([]any, len(sort))
+	for i, key := range sort {
+		switch key.Field {
+		case SortCreatedAt:
+			// created_at has no time zone and is read back as UTC, the comparison has to use the same wall clock
+			values[i], err = time.ParseInLocation(cursorTimeLayout, payload.Values[i], time.UTC)
+		case SortAgeInMonth:
+			values[i], err = strconv.Atoi(payload.Values[i])
+		default:
+			values[i] = payload.Values[i]
+		}
+		if err != nil {
+			return Cursor{}, ErrInvalidCursor
+		}
+	}
+
+	return Cursor{Sort: sort, Values: values, ID: id}, nil
+}
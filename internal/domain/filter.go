@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FilterField names a cat attribute a Filter can constrain, the repository maps it to a column.
+type FilterField string
+
+const (
+	FilterAgeInMonth FilterField = "ageInMonth"
+	FilterRace       FilterField = "race"
+	FilterSex        FilterField = "sex"
+	FilterCreatedAt  FilterField = "createdAt"
+)
+
+type CompareOp string
+
+const (
+	OpEqual          CompareOp = "="
+	OpNotEqual       CompareOp = "!="
+	OpGreater        CompareOp = ">"
+	OpGreaterOrEqual CompareOp = ">="
+	OpLess           CompareOp = "<"
+	OpLessOrEqual    CompareOp = "<="
+)
+
+// compareOps is ordered so two character operators are matched before their prefixes.
+var compareOps = []CompareOp{OpGreaterOrEqual, OpLessOrEqual, OpNotEqual, OpGreater, OpLess, OpEqual}
+
+// Predicate is a node of a Filter, it's one of IntComparison, TimeComparison or ValueSet.
+type Predicate interface {
+	predicate()
+}
+
+// IntComparison compares a numeric field with a value.
+type IntComparison struct {
+	Field FilterField
+	Op    CompareOp
+	Value int
+}
+
+// TimeComparison compares a timestamp field with a value.
+type TimeComparison struct {
+	Field FilterField
+	Op    CompareOp
+	Value time.Time
+}
+
+// ValueSet matches a field against a list of values, or excludes them when Negate is set.
+type ValueSet struct {
+	Field  FilterField
+	Values []string
+	Negate bool
+}
+
+func (IntComparison) predicate()  {}
+func (TimeComparison) predicate() {}
+func (ValueSet) predicate()       {}
+
+// Filter holds predicates that must all hold.
+type Filter struct {
+	Predicates []Predicate
+}
+
+func (f Filter) IsEmpty() bool {
+	return len(f.Predicates) == 0
+}
+
+// ParseIntRange parses comma separated comparisons such as ">=6,<=24", a value without
+// an operator is compared for equality.
+func ParseIntRange(field FilterField, s string) ([]Predicate, error) {
+	predicates := make([]Predicate, 0)
+	for _, term := range strings.Split(s, ",") {
+		op, value := splitCompareOp(strings.TrimSpace(term))
+
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s filter: %s", field, term)
+		}
+
+		predicates = append(predicates, IntComparison{Field: field, Op: op, Value: number})
+	}
+
+	return predicates, nil
+}
+
+// ParseDateRange parses comma separated comparisons against dates such as ">=2024-01-01,<2024-02-01".
+// A date covers the whole day, so "<=2024-01-31" includes the 31st and "=2024-01-31" matches that day.
+func ParseDateRange(field FilterField, s string) ([]Predicate, error) {
+	predicates := make([]Predicate, 0)
+	for _, term := range strings.Split(s, ",") {
+		op, value := splitCompareOp(strings.TrimSpace(term))
+
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s filter: %s", field, term)
+		}
+		nextDay := day.AddDate(0, 0, 1)
+
+		switch op {
+		case OpGreaterOrEqual, OpLess:
+			predicates = append(predicates, TimeComparison{Field: field, Op: op, Value: day})
+		case OpGreater:
+			predicates = append(predicates, TimeComparison{Field: field, Op: OpGreaterOrEqual, Value: nextDay})
+		case OpLessOrEqual:
+			predicates = append(predicates, TimeComparison{Field: field, Op: OpLess, Value: nextDay})
+		case OpEqual:
+			predicates = append(predicates,
+				TimeComparison{Field: field, Op: OpGreaterOrEqual, Value: day},
+				TimeComparison{Field: field, Op: OpLess, Value: nextDay},
+			)
+		default:
+			return nil, fmt.Errorf("unsupported %s filter operator: %s", field, op)
+		}
+	}
+
+	return predicates, nil
+}
+
+// ParseValueSet parses comma separated values such as "Persian,Bengal", values prefixed
+// with "!" are excluded instead. Every value is checked with validate.
+func ParseValueSet(field FilterField, s string, validate func(string) error) ([]Predicate, error) {
+	include := ValueSet{Field: field}
+	exclude := ValueSet{Field: field, Negate: true}
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+
+		value, negate := strings.CutPrefix(term, "!")
+		if value == "" {
+			return nil, fmt.Errorf("invalid %s filter: %s", field, s)
+		}
+		if err := validate(value); err != nil {
+			return nil, err
+		}
+
+		if negate {
+			exclude.Values = append(exclude.Values, value)
+		} else {
+			include.Values = append(include.Values, value)
+		}
+	}
+
+	predicates := make([]Predicate, 0, 2)
+	if len(include.Values) > 0 {
+		predicates = append(predicates, include)
+	}
+	if len(exclude.Values) > 0 {
+		predicates = append(predicates, exclude)
+	}
+
+	return predicates, nil
+}
+
+func splitCompareOp(term string) (CompareOp, string) {
+	for _, op := range compareOps {
+		if value, ok := strings.CutPrefix(term, string(op)); ok {
+			return op, strings.TrimSpace(value)
+		}
+	}
+
+	return OpEqual, term
+}
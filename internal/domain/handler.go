@@ -2,7 +2,7 @@ package domain
 
 import (
 	"errors"
-	"strconv"
+	"fmt"
 
 	"github.com/oklog/ulid/v2"
 	"go.uber.org/multierr"
@@ -29,16 +29,36 @@ func (b boolQueryParam) validate() error {
 	return nil
 }
 
+// PageMeta describes a page of a list, Total counts every result matching the filter
+// regardless of the page. Next points at the last row when another page follows.
+type PageMeta struct {
+	Limit   int
+	Offset  int
+	Total   int
+	HasNext bool
+	Next    Cursor
+}
+
 type QueryParam struct {
 	ID         ulid.ULID      `query:"id"`
 	Limit      int            `query:"limit"`
 	Offset     int            `query:"offset"`
-	Race       CatRace        `query:"race"`
-	Sex        CatSex         `query:"sex"`
+	Race       string         `query:"race"`
+	Sex        string         `query:"sex"`
 	HasMatched boolQueryParam `query:"hasMatched"`
 	AgeInMonth string         `query:"ageInMonth"`
+	CreatedAt  string         `query:"createdAt"`
 	Owned      boolQueryParam `query:"owned"`
 	Search     string         `query:"search"`
+	Highlight  boolQueryParam `query:"highlight"`
+	Sort       string         `query:"sort"`
+	Cursor     string         `query:"cursor"`
+	// SortKeys is the parsed Sort, empty when search results are ordered by rank
+	SortKeys Sort `query:"-"`
+	// After is the decoded Cursor, rows up to and including it are skipped
+	After Cursor `query:"-"`
+	// Filter is parsed from Race, Sex, AgeInMonth and CreatedAt
+	Filter Filter `query:"-"`
 }
 
 func (p *QueryParam) Validate() error {
@@ -48,28 +68,53 @@ func (p *QueryParam) Validate() error {
 		p.Limit = 5
 	}
 
-	if p.Race != "" {
-		if err := p.Race.Validate(); err != nil {
+	if p.Limit < 0 || p.Offset < 0 {
+		errs = multierr.Append(errs, errors.New("limit and offset must not be negative"))
+	}
+
+	if p.Highlight != "" {
+		if err := p.Highlight.validate(); err != nil {
 			errs = multierr.Append(errs, err)
 		}
 	}
 
-	if p.Sex != "" {
-		if err := p.Sex.Validate(); err != nil {
+	switch {
+	case p.Sort != "":
+		sort, err := ParseSort(p.Sort)
+		if err != nil {
 			errs = multierr.Append(errs, err)
 		}
+		p.SortKeys = sort
+	case p.Search == "":
+		p.SortKeys = DefaultSort
 	}
 
-	if p.HasMatched != "" {
-		if err := p.HasMatched.validate(); err != nil {
+	// search results without a sort are ordered by rank, which a cursor can't follow
+	if p.Cursor != "" && p.Search != "" && p.Sort == "" {
+		errs = multierr.Append(errs, errors.New("cursor can't be combined with search unless sort is set, use offset"))
+	}
+
+	if p.Cursor != "" {
+		after, err := DecodeCursor(p.Cursor)
+		if err == nil && after.Sort.String() != p.SortKeys.String() {
+			err = fmt.Errorf("%w: cursor was issued for another sort order", ErrInvalidCursor)
+		}
+		if err != nil {
 			errs = multierr.Append(errs, err)
 		}
+		p.After = after
 	}
 
-	if err := p.validateAgeInMonth(); err != nil {
+	if err := p.parseFilter(); err != nil {
 		errs = multierr.Append(errs, err)
 	}
 
+	if p.HasMatched != "" {
+		if err := p.HasMatched.validate(); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+
 	if p.Owned != "" {
 		if err := p.Owned.validate(); err != nil {
 			errs = multierr.Append(errs, err)
@@ -83,17 +128,38 @@ func (p *QueryParam) Validate() error {
 	return nil
 }
 
-func (p *QueryParam) validateAgeInMonth() error {
-	if p.AgeInMonth == "" {
-		return nil
-	}
+// parseFilter builds Filter, e.g. race=Persian,!Sphynx&ageInMonth=>=6,<=24&createdAt=>=2024-01-01
+func (p *QueryParam) parseFilter() error {
+	var errs error
 
-	// Check for '>' | '<' | '=' followed by a numeric string
-	if len(p.AgeInMonth) > 1 && p.AgeInMonth[0] == '>' || p.AgeInMonth[0] == '<' || p.AgeInMonth[0] == '=' {
-		if _, err := strconv.Atoi(p.AgeInMonth[1:]); err == nil {
-			return nil
+	p.Filter = Filter{}
+	appendPredicates := func(predicates []Predicate, err error) {
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			return
 		}
+		p.Filter.Predicates = append(p.Filter.Predicates, predicates...)
+	}
+
+	if p.Race != "" {
+		appendPredicates(ParseValueSet(FilterRace, p.Race, func(v string) error {
+			return CatRace(v).Validate()
+		}))
+	}
+
+	if p.Sex != "" {
+		appendPredicates(ParseValueSet(FilterSex, p.Sex, func(v string) error {
+			return CatSex(v).Validate()
+		}))
+	}
+
+	if p.AgeInMonth != "" {
+		appendPredicates(ParseIntRange(FilterAgeInMonth, p.AgeInMonth))
+	}
+
+	if p.CreatedAt != "" {
+		appendPredicates(ParseDateRange(FilterCreatedAt, p.CreatedAt))
 	}
 
-	return errors.New("invalid age in month query param")
+	return errs
 }
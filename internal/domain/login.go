@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials   = errors.New("invalid email or password")
+	ErrTooManyLoginAttempts = errors.New("too many failed login attempts")
+)
+
+// LoginLockedError is returned while an email address or client IP is locked out.
+// It unwraps to ErrTooManyLoginAttempts and tells the client when to try again.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e LoginLockedError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e LoginLockedError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+type LoginThrottle struct {
+	MaxAttempts int
+	Window      time.Duration
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+// Lockout returns how long a key stays locked after the given number of consecutive failures.
+// The first lockout starts at MaxAttempts failures and doubles with every further failure.
+func (t LoginThrottle) Lockout(failures int) time.Duration {
+	if t.MaxAttempts <= 0 || failures < t.MaxAttempts {
+		return 0
+	}
+
+	lockout := t.BaseLockout
+	for i := t.MaxAttempts; i < failures && lockout < t.MaxLockout; i++ {
+		lockout *= 2
+	}
+
+	if t.MaxLockout > 0 && lockout > t.MaxLockout {
+		lockout = t.MaxLockout
+	}
+
+	return lockout
+}
+
+func LoginEmailKey(email string) string {
+	return "email:" + email
+}
+
+func LoginIPKey(ip string) string {
+	return "ip:" + ip
+}
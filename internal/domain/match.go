@@ -31,3 +31,40 @@ type DetailMatch struct {
 	MatchCat Cat
 	UserCat  Cat
 }
+
+// defaultMatchPageSize is the page size of a cursor given without a limit.
+const defaultMatchPageSize = 5
+
+// MatchQueryParam pages the matches of a user.
+// Without limit and cursor every match is returned, the way GET /cat/match always did.
+type MatchQueryParam struct {
+	Limit  int    `query:"limit"`
+	Offset int    `query:"offset"`
+	Cursor string `query:"cursor"`
+	// After is the decoded Cursor, matches up to and including it are skipped
+	After Cursor `query:"-"`
+}
+
+func (p *MatchQueryParam) Validate() error {
+	if p.Limit < 0 || p.Offset < 0 {
+		return errors.New("limit and offset must not be negative")
+	}
+
+	if p.Limit == 0 && p.Cursor != "" {
+		p.Limit = defaultMatchPageSize
+	}
+
+	// matches are always listed newest first
+	if p.Cursor != "" {
+		after, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			return err
+		}
+		if after.Sort.String() != DefaultSort.String() {
+			return ErrInvalidCursor
+		}
+		p.After = after
+	}
+
+	return nil
+}
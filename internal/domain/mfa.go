@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrMFANotSetUp       = errors.New("two-factor authentication has not been set up")
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrInvalidMFACode    = errors.New("invalid two-factor authentication code")
+	ErrInvalidMFAToken   = errors.New("invalid or expired two-factor authentication token")
+)
+
+type UserMFA struct {
+	UserID       ulid.ULID
+	Secret       string
+	Enabled      bool
+	LastUsedStep int64
+}
+
+type MFASetup struct {
+	Secret string
+	URI    string
+}
+
+func LoginMFAKey(userID ulid.ULID) string {
+	return "mfa:" + userID.String()
+}
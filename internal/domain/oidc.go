@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrOIDCNotConfigured    = errors.New("openid connect login is not configured")
+	ErrInvalidOIDCState     = errors.New("invalid or expired openid connect login state")
+	ErrOIDCEmailNotVerified = errors.New("identity provider did not return a verified email address")
+	ErrOIDCLoginFailed      = errors.New("openid connect login failed")
+	// ErrOIDCAccountNotVerified guards against linking an account someone else may have
+	// registered with the email before its owner, together with a password they know.
+	ErrOIDCAccountNotVerified = errors.New("an unverified account already uses this email address, verify it or reset its password first")
+	ErrIdentityNotFound       = errors.New("identity not found")
+)
+
+// OIDCLoginState is kept between redirecting to the identity provider and its callback.
+type OIDCLoginState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// UserIdentity links a user to the subject of an identity provider.
+type UserIdentity struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
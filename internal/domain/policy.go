@@ -0,0 +1,111 @@
+package domain
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/multierr"
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+var commonPasswords = sync.OnceValue(func() map[string]struct{} {
+	passwords := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = struct{}{}
+	}
+
+	return passwords
+})
+
+// CredentialPolicy holds the rules for passwords and profile names chosen by users.
+// Lengths are counted in characters, a zero length disables that bound.
+type CredentialPolicy struct {
+	PasswordMinLength     int
+	PasswordMaxLength     int
+	RequireUpper          bool
+	RequireLower          bool
+	RequireDigit          bool
+	RequireSymbol         bool
+	RejectCommonPasswords bool
+	NameMinLength         int
+	NameMaxLength         int
+}
+
+// ValidatePassword returns every rule a new password breaks, field names the password in the messages.
+func (p CredentialPolicy) ValidatePassword(field, password string) error {
+	var errs error
+
+	if err := validateLength(field, password, p.PasswordMinLength, p.PasswordMaxLength); err != nil {
+		errs = multierr.Append(errs, err)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case !unicode.IsLetter(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		errs = multierr.Append(errs, fmt.Errorf("%s must contain an uppercase letter", field))
+	}
+	if p.RequireLower && !hasLower {
+		errs = multierr.Append(errs, fmt.Errorf("%s must contain a lowercase letter", field))
+	}
+	if p.RequireDigit && !hasDigit {
+		errs = multierr.Append(errs, fmt.Errorf("%s must contain a digit", field))
+	}
+	if p.RequireSymbol && !hasSymbol {
+		errs = multierr.Append(errs, fmt.Errorf("%s must contain a symbol", field))
+	}
+
+	if p.RejectCommonPasswords {
+		if _, ok := commonPasswords()[strings.ToLower(password)]; ok {
+			errs = multierr.Append(errs, fmt.Errorf("%s is too common, choose a less predictable one", field))
+		}
+	}
+
+	return errs
+}
+
+// ValidateLoginPassword only bounds the length of a password being checked against a stored one,
+// passwords chosen under an older policy must keep working.
+func (p CredentialPolicy) ValidateLoginPassword(field, password string) error {
+	return validateLength(field, password, 0, p.PasswordMaxLength)
+}
+
+func (p CredentialPolicy) ValidateName(field, name string) error {
+	return validateLength(field, name, p.NameMinLength, p.NameMaxLength)
+}
+
+func validateLength(field, value string, minLength, maxLength int) error {
+	length := utf8.RuneCountInString(value)
+
+	switch {
+	case minLength > 0 && maxLength > 0 && (length < minLength || length > maxLength):
+		return fmt.Errorf("%s must be between %d and %d characters", field, minLength, maxLength)
+	case minLength > 0 && length < minLength:
+		return fmt.Errorf("%s must be at least %d characters", field, minLength)
+	case maxLength > 0 && length > maxLength:
+		return fmt.Errorf("%s must be at most %d characters", field, maxLength)
+	}
+
+	return nil
+}
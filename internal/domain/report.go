@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrReportNotFound        = errors.New("report not found")
+	ErrReportTargetNotFound  = errors.New("reported content not found")
+	ErrReportOwnTarget       = errors.New("you can't report yourself or your own content")
+	ErrDuplicateReport       = errors.New("you already reported this content")
+	ErrReportAlreadyResolved = errors.New("report already resolved")
+	ErrReportActionNotValid  = errors.New("action doesn't apply to the reported content")
+)
+
+type ReportTargetType string
+
+const (
+	ReportTargetCat   ReportTargetType = "cat"
+	ReportTargetMatch ReportTargetType = "match"
+	ReportTargetUser  ReportTargetType = "user"
+)
+
+func (t ReportTargetType) Validate() error {
+	validTargetType := map[ReportTargetType]struct{}{
+		ReportTargetCat:   {},
+		ReportTargetMatch: {},
+		ReportTargetUser:  {},
+	}
+	defer clear(validTargetType)
+
+	if _, ok := validTargetType[t]; !ok {
+		return fmt.Errorf("invalid report target type: %s", t)
+	}
+
+	return nil
+}
+
+type ReportReason string
+
+const (
+	ReportReasonSpam          ReportReason = "spam"
+	ReportReasonHarassment    ReportReason = "harassment"
+	ReportReasonInappropriate ReportReason = "inappropriate"
+	ReportReasonScam          ReportReason = "scam"
+	ReportReasonOther         ReportReason = "other"
+)
+
+func (r ReportReason) Validate() error {
+	validReason := map[ReportReason]struct{}{
+		ReportReasonSpam:          {},
+		ReportReasonHarassment:    {},
+		ReportReasonInappropriate: {},
+		ReportReasonScam:          {},
+		ReportReasonOther:         {},
+	}
+	defer clear(validReason)
+
+	if _, ok := validReason[r]; !ok {
+		return fmt.Errorf("invalid report reason: %s", r)
+	}
+
+	return nil
+}
+
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+func (s ReportStatus) Validate() error {
+	validStatus := map[ReportStatus]struct{}{
+		ReportStatusOpen:      {},
+		ReportStatusActioned:  {},
+		ReportStatusDismissed: {},
+	}
+	defer clear(validStatus)
+
+	if _, ok := validStatus[s]; !ok {
+		return fmt.Errorf("invalid report status: %s", s)
+	}
+
+	return nil
+}
+
+// ReportAction is what a moderator did about a report, every action except
+// dismiss marks the report as actioned.
+type ReportAction string
+
+const (
+	ReportActionHideCat       ReportAction = "hideCat"
+	ReportActionWithdrawMatch ReportAction = "withdrawMatch"
+	ReportActionSuspendUser   ReportAction = "suspendUser"
+	ReportActionDismiss       ReportAction = "dismiss"
+)
+
+func (a ReportAction) Validate() error {
+	validAction := map[ReportAction]struct{}{
+		ReportActionHideCat:       {},
+		ReportActionWithdrawMatch: {},
+		ReportActionSuspendUser:   {},
+		ReportActionDismiss:       {},
+	}
+	defer clear(validAction)
+
+	if _, ok := validAction[a]; !ok {
+		return fmt.Errorf("invalid report action: %s", a)
+	}
+
+	return nil
+}
+
+// Status returns the status of a report resolved with the action.
+func (a ReportAction) Status() ReportStatus {
+	if a == ReportActionDismiss {
+		return ReportStatusDismissed
+	}
+
+	return ReportStatusActioned
+}
+
+type Report struct {
+	ID         ulid.ULID
+	ReporterID ulid.ULID
+	TargetType ReportTargetType
+	TargetID   ulid.ULID
+	Reason     ReportReason
+	Details    string
+	Status     ReportStatus
+	Action     ReportAction
+	ResolvedBy ulid.ULID
+	ResolvedAt time.Time
+	CreatedAt  time.Time
+}
+
+type ReportQueryParam struct {
+	Status     ReportStatus     `query:"status"`
+	TargetType ReportTargetType `query:"targetType"`
+	Limit      int              `query:"limit"`
+	Offset     int              `query:"offset"`
+}
+
+func (p *ReportQueryParam) Validate() error {
+	if p.Limit == 0 {
+		p.Limit = 10
+	}
+
+	if p.Limit < 0 || p.Offset < 0 {
+		return errors.New("limit and offset must not be negative")
+	}
+
+	if p.Status != "" {
+		if err := p.Status.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.TargetType != "" {
+		if err := p.TargetType.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const maxUserAgentLength = 512
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// Session is a single login of a user, its ID is also the family ID of the refresh
+// tokens issued for it and the sid claim of its access tokens.
+type Session struct {
+	ID         ulid.ULID
+	UserID     ulid.ULID
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// SessionClient describes the device a login comes from.
+type SessionClient struct {
+	UserAgent string
+	IPAddress string
+}
+
+func NewSessionClient(userAgent, ipAddress string) SessionClient {
+	if runes := []rune(userAgent); len(runes) > maxUserAgentLength {
+		userAgent = string(runes[:maxUserAgentLength])
+	}
+
+	return SessionClient{
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+}
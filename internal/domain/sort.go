@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField names a cat attribute listings can be ordered by, the repository maps it to a column.
+type SortField string
+
+const (
+	SortCreatedAt  SortField = "createdAt"
+	SortAgeInMonth SortField = "ageInMonth"
+	SortName       SortField = "name"
+	SortRace       SortField = "race"
+)
+
+func (f SortField) Validate() error {
+	validField := map[SortField]struct{}{
+		SortCreatedAt:  {},
+		SortAgeInMonth: {},
+		SortName:       {},
+		SortRace:       {},
+	}
+	defer clear(validField)
+
+	if _, ok := validField[f]; !ok {
+		return fmt.Errorf("invalid sort field: %s", f)
+	}
+
+	return nil
+}
+
+// value returns the attribute of the cat the field sorts by.
+func (f SortField) value(c Cat) any {
+	switch f {
+	case SortAgeInMonth:
+		return c.AgeInMonth
+	case SortName:
+		return c.Name
+	case SortRace:
+		return string(c.Race)
+	default:
+		return c.CreatedAt
+	}
+}
+
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+// Sort orders a listing by its keys in turn, ties left by every key are broken by id.
+type Sort []SortKey
+
+// DefaultSort lists the newest cats first.
+var DefaultSort = Sort{{Field: SortCreatedAt, Desc: true}}
+
+// ParseSort parses comma separated fields such as "-ageInMonth,name", a leading "-" sorts descending.
+func ParseSort(s string) (Sort, error) {
+	sort := make(Sort, 0)
+	seen := make(map[SortField]struct{})
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+
+		name, desc := strings.CutPrefix(term, "-")
+		field := SortField(name)
+		if err := field.Validate(); err != nil {
+			return nil, err
+		}
+
+		if _, ok := seen[field]; ok {
+			return nil, fmt.Errorf("duplicate sort field: %s", field)
+		}
+		seen[field] = struct{}{}
+
+		sort = append(sort, SortKey{Field: field, Desc: desc})
+	}
+
+	return sort, nil
+}
+
+// String returns the sort in the form ParseSort accepts.
+func (s Sort) String() string {
+	terms := make([]string, len(s))
+	for i, key := range s {
+		terms[i] = string(key.Field)
+		if key.Desc {
+			terms[i] = "-" + terms[i]
+		}
+	}
+
+	return strings.Join(terms, ",")
+}
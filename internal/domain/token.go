@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	AccessTokenFromToken = "loggedInToken"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrTokenRevoked        = errors.New("token has been revoked")
+	ErrInvalidResetToken   = errors.New("invalid or expired password reset token")
+)
+
+// AccessToken identifies an issued access token by its jti.
+// The jti is a ULID, so it also carries the time the token was issued.
+type AccessToken struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	SessionID ulid.ULID
+	ExpiresAt time.Time
+}
+
+type RefreshToken struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	FamilyID  ulid.ULID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	RevokedAt time.Time
+	CreatedAt time.Time
+	// Expired is set by postgres when reading the token, ExpiresAt has no time zone
+	// and can't be compared with time.Now
+	Expired bool
+}
+
+type AuthToken struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type PasswordResetToken struct {
+	ID        ulid.ULID
+	UserID    ulid.ULID
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    time.Time
+	CreatedAt time.Time
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
@@ -11,16 +13,89 @@ const (
 	UserFromToken = "loggedInUser"
 )
 
+const (
+	EmailNotVerifiedCode = "EMAIL_NOT_VERIFIED"
+)
+
+type Role string
+
+const (
+	RoleAdmin Role = "admin"
+)
+
 var (
 	DuplicateEmailError = errors.New("email already exists")
 	UserNotFoundError   = errors.New("user not found")
 	InvalidPassword     = errors.New("invalid password")
+	// ErrPasswordNotSet is returned to users created through OpenID Connect, they confirm
+	// password protected actions once they set a password through the password reset.
+	ErrPasswordNotSet = errors.New("account has no password, set one through the password reset first")
+
+	ErrEmailNotVerified         = errors.New("email address has not been verified")
+	ErrEmailAlreadyVerified     = errors.New("email address is already verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrForbidden                = errors.New("you don't have permission to access this resource")
+	ErrUserSuspended            = errors.New("account suspended")
 )
 
 type User struct {
-	ID        ulid.ULID
-	Email     string
-	Name      string
-	Password  string
-	CreatedAt time.Time
+	ID         ulid.ULID
+	Email      string
+	Name       string
+	Password   string
+	Verified   bool
+	Roles      []Role
+	MFAEnabled bool
+	Suspended  bool
+	CreatedAt  time.Time
+}
+
+// NormalizeEmail returns the canonical form of an email address, every email
+// is normalized before it is stored or looked up, so addresses differing
+// only in letter case or surrounding whitespace belong to the same account.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// HasRole reports whether the user has at least one of the given roles.
+func (u User) HasRole(roles ...Role) bool {
+	for _, role := range roles {
+		if slices.Contains(u.Roles, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type UserQueryParam struct {
+	Limit  int    `query:"limit"`
+	Offset int    `query:"offset"`
+	Search string `query:"search"`
+}
+
+func (p *UserQueryParam) Validate() error {
+	if p.Limit == 0 {
+		p.Limit = 10
+	}
+
+	if p.Limit < 0 || p.Offset < 0 {
+		return errors.New("limit and offset must not be negative")
+	}
+
+	return nil
+}
+
+type UserProfile struct {
+	User
+	CatCount   int
+	MatchCount int
+}
+
+// OwnerProfile is the public view of a user shown to other users, EmailVisible
+// is only set when the viewer may see the owner's email address.
+type OwnerProfile struct {
+	User
+	Cats         []Cat
+	EmailVisible bool
 }
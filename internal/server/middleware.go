@@ -12,12 +12,13 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/oklog/ulid/v2"
 	"go.uber.org/zap"
 
-	"cats-social/common/configs"
 	"cats-social/common/id"
 	"cats-social/common/logger"
 	"cats-social/common/security"
+	userRepo "cats-social/internal/application/user/repository"
 	"cats-social/internal/domain"
 )
 
@@ -95,23 +96,65 @@ func eTagMiddleware() fiber.Handler {
 	return etag.New()
 }
 
-func jwtMiddleware() fiber.Handler {
+func jwtMiddleware(
+	revocationRepository userRepo.RevocationRepositoryContract,
+	sessionRepository userRepo.SessionRepositoryContract,
+) fiber.Handler {
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{
-			JWTAlg: jwtware.HS256,
-			Key:    []byte(configs.Runtime.API.JWT.JWTSecret),
-		},
+		KeyFunc:    security.Keyfunc,
 		Claims:     &security.AccessTokenClaims{},
 		ContextKey: accessToken,
 		SuccessHandler: func(c *fiber.Ctx) error {
 			claims := c.Locals(accessToken).(*jwt.Token).Claims.(*security.AccessTokenClaims)
 
+			// access tokens carry no audience, tokens minted for other purposes (e.g. email verification) do
+			jti, err := ulid.Parse(claims.ID)
+			if err != nil || claims.ExpiresAt == nil || len(claims.Audience) > 0 {
+				return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+					"message": "invalid token claims",
+				})
+			}
+
+			token := domain.AccessToken{
+				ID:        jti,
+				UserID:    claims.User.ID,
+				SessionID: claims.SessionID,
+				ExpiresAt: claims.ExpiresAt.Time,
+			}
+
+			revoked, err := revocationRepository.IsRevoked(c.UserContext(), token)
+			if err != nil {
+				return c.Status(http.StatusInternalServerError).JSON(fiber.Map{
+					"message": domain.InternalServerErrorMessage,
+				})
+			}
+			if revoked {
+				return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+					"message": domain.ErrTokenRevoked.Error(),
+				})
+			}
+
+			// last seen is informational, a failed update must not fail the request
+			if token.SessionID != (ulid.ULID{}) {
+				if err = sessionRepository.Touch(c.UserContext(), token.SessionID, c.IP()); err != nil {
+					logger.FromCtx(c.UserContext()).Error("error touch session", zap.Error(err))
+				}
+			}
+
+			roles := make([]domain.Role, len(claims.Roles))
+			for i, role := range claims.Roles {
+				roles[i] = domain.Role(role)
+			}
+
 			user := domain.User{
-				ID:    claims.User.ID,
-				Email: claims.User.Email,
-				Name:  claims.User.Name,
+				ID:       claims.User.ID,
+				Email:    claims.User.Email,
+				Name:     claims.User.Name,
+				Verified: claims.Verified,
+				Roles:    roles,
 			}
 			c.Locals(domain.UserFromToken, user)
+			c.Locals(domain.AccessTokenFromToken, token)
 			return c.Next()
 		},
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
@@ -121,3 +164,37 @@ func jwtMiddleware() fiber.Handler {
 		},
 	})
 }
+
+// verifiedMiddleware must run after jwtMiddleware, it rejects accounts
+// whose email address has not been verified yet.
+func verifiedMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals(domain.UserFromToken).(domain.User)
+		if !user.Verified {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"message": domain.ErrEmailNotVerified.Error(),
+				"data": fiber.Map{
+					"error": domain.ErrEmailNotVerified.Error(),
+					"code":  domain.EmailNotVerifiedCode,
+				},
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// requireRole must run after jwtMiddleware, it rejects users
+// that have none of the given roles.
+func requireRole(roles ...domain.Role) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user := c.Locals(domain.UserFromToken).(domain.User)
+		if !user.HasRole(roles...) {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"message": domain.ErrForbidden.Error(),
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -15,7 +15,10 @@ import (
 
 	"cats-social/common/configs"
 	"cats-social/common/database"
+	"cats-social/common/security"
 	"cats-social/internal/application"
+	userRepo "cats-social/internal/application/user/repository"
+	"cats-social/internal/domain"
 )
 
 const (
@@ -32,6 +35,11 @@ func Run() {
 		log.Panic("Failed to connect to database", zap.Error(err))
 	}
 
+	err = security.LoadKeys()
+	if err != nil {
+		log.Panic("Failed to load JWT keys", zap.Error(err))
+	}
+
 	serverTimeout := time.Duration(configs.Runtime.API.Timeout) * time.Second
 	serverConfig := fiber.Config{
 		AppName:            configs.Runtime.App.Name,
@@ -56,9 +64,21 @@ func Run() {
 		serverConfig.Prefork = true
 	}
 
+	revocationCacheTTL := time.Duration(configs.Runtime.API.JWT.RevocationCacheTTL) * time.Second
+	revocationRepository := userRepo.NewRevocationRepository(db, revocationCacheTTL)
+	sessionRepository := userRepo.NewSessionRepository(db)
+
 	app := fiber.New(serverConfig)
 	setMiddlewares(app)
-	application.New(app, db, jwtMiddleware())
+	application.New(
+		app,
+		db,
+		revocationRepository,
+		sessionRepository,
+		jwtMiddleware(revocationRepository, sessionRepository),
+		verifiedMiddleware(),
+		requireRole(domain.RoleAdmin),
+	)
 	log.Debug("Server Config", zap.Any("Config", app.Config()))
 
 	go func() {
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS refresh_tokens
+(
+    id         bytea       NOT NULL PRIMARY KEY,
+    user_id    bytea       NOT NULL,
+    family_id  bytea       NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL,
+    updated_at TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
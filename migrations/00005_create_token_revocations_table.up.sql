@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        bytea     NOT NULL PRIMARY KEY,
+    user_id    bytea     NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens (expires_at);
+
+CREATE TABLE IF NOT EXISTS user_token_revocations
+(
+    user_id        bytea     NOT NULL PRIMARY KEY,
+    revoked_before TIMESTAMP NOT NULL,
+    created_at     TIMESTAMP NOT NULL,
+    updated_at     TIMESTAMP NOT NULL
+);
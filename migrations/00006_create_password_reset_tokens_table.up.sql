@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS password_reset_tokens
+(
+    id         bytea       NOT NULL PRIMARY KEY,
+    user_id    bytea       NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP   NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL,
+    updated_at TIMESTAMP   NOT NULL
+);
+
+CREATE INDEX idx_password_reset_tokens_user_id ON password_reset_tokens (user_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_email_deleted_at_null;
+
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+
+-- emails of deleted accounts can be registered again
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_deleted_at_null ON users (email) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS login_attempts
+(
+    key            VARCHAR(320) NOT NULL PRIMARY KEY,
+    failed_count   INTEGER      NOT NULL,
+    last_failed_at TIMESTAMP    NOT NULL,
+    locked_until   TIMESTAMP,
+    created_at     TIMESTAMP    NOT NULL,
+    updated_at     TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX idx_login_attempts_last_failed_at ON login_attempts (last_failed_at);
\ No newline at end of file
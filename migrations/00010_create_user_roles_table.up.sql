@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id    bytea       NOT NULL,
+    role       VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP   NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+-- roles are granted manually, e.g.
+-- INSERT INTO user_roles (user_id, role, created_at) SELECT id, 'admin', NOW() FROM users WHERE email = '...';
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS user_mfa
+(
+    user_id        bytea       NOT NULL PRIMARY KEY,
+    secret         VARCHAR(64) NOT NULL,
+    last_used_step BIGINT      NOT NULL,
+    enabled_at     TIMESTAMP,
+    created_at     TIMESTAMP   NOT NULL,
+    updated_at     TIMESTAMP   NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes
+(
+    id         bytea       NOT NULL PRIMARY KEY,
+    user_id    bytea       NOT NULL,
+    code_hash  VARCHAR(64) NOT NULL,
+    used_at    TIMESTAMP,
+    created_at TIMESTAMP   NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_mfa_recovery_codes_user_id_code_hash ON mfa_recovery_codes (user_id, code_hash);
\ No newline at end of file
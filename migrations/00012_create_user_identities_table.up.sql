@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS user_identities
+(
+    id         bytea        NOT NULL PRIMARY KEY,
+    user_id    bytea        NOT NULL,
+    issuer     VARCHAR(255) NOT NULL,
+    subject    VARCHAR(255) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL,
+    updated_at TIMESTAMP    NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_user_identities_issuer_subject ON user_identities (issuer, subject);
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS oidc_login_states
+(
+    state_hash    VARCHAR(64)  NOT NULL PRIMARY KEY,
+    nonce         VARCHAR(64)  NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    expires_at    TIMESTAMP    NOT NULL,
+    created_at    TIMESTAMP    NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- a session is one login, its id is the family id of the refresh tokens issued for it
+CREATE TABLE IF NOT EXISTS sessions
+(
+    id           bytea        NOT NULL PRIMARY KEY,
+    user_id      bytea        NOT NULL,
+    user_agent   VARCHAR(512) NOT NULL,
+    ip_address   VARCHAR(45)  NOT NULL,
+    created_at   TIMESTAMP    NOT NULL,
+    last_seen_at TIMESTAMP    NOT NULL,
+    revoked_at   TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
\ No newline at end of file
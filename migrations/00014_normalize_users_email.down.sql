@@ -0,0 +1,4 @@
+-- emails stay normalized, only the case-sensitive index is restored
+DROP INDEX IF EXISTS idx_users_lower_email_deleted_at_null;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_deleted_at_null ON users (email) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,26 @@
+-- active accounts whose emails only differ in letter case or surrounding whitespace can't be
+-- normalized automatically, they are reported here and have to be merged or renamed by hand
+DO $$
+DECLARE
+    collisions TEXT;
+BEGIN
+    SELECT string_agg(emails, '; ')
+    INTO collisions
+    FROM (SELECT string_agg(email, ', ' ORDER BY created_at) AS emails
+          FROM users
+          WHERE deleted_at IS NULL
+          GROUP BY lower(trim(email))
+          HAVING count(*) > 1) c;
+
+    IF collisions IS NOT NULL THEN
+        RAISE EXCEPTION 'users with case-insensitive duplicate emails: %', collisions
+            USING HINT = 'merge or rename these accounts, then run the migration again';
+    END IF;
+END
+$$;
+
+UPDATE users SET email = lower(trim(email)) WHERE email <> lower(trim(email));
+
+DROP INDEX IF EXISTS idx_users_email_deleted_at_null;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_lower_email_deleted_at_null ON users (lower(email)) WHERE deleted_at IS NULL;
\ No newline at end of file
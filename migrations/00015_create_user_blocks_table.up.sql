@@ -0,0 +1,10 @@
+-- a block is one sided, but hides cats and prevents matches in both directions
+CREATE TABLE IF NOT EXISTS user_blocks
+(
+    blocker_id bytea     NOT NULL,
+    blocked_id bytea     NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE INDEX idx_user_blocks_blocked_id ON user_blocks (blocked_id);
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS reports
+(
+    id          bytea         NOT NULL PRIMARY KEY,
+    reporter_id bytea         NOT NULL,
+    target_type VARCHAR(10)   NOT NULL,
+    target_id   bytea         NOT NULL,
+    reason      VARCHAR(20)   NOT NULL,
+    details     VARCHAR(1000) NOT NULL,
+    status      VARCHAR(10)   NOT NULL DEFAULT 'open',
+    action      VARCHAR(20),
+    resolved_by bytea,
+    resolved_at TIMESTAMP,
+    created_at  TIMESTAMP     NOT NULL,
+    updated_at  TIMESTAMP     NOT NULL
+);
+
+CREATE INDEX idx_reports_status_created_at ON reports (status, created_at);
+CREATE INDEX idx_reports_target ON reports (target_type, target_id);
+-- a user can only have one open report per target
+CREATE UNIQUE INDEX idx_reports_reporter_target_open ON reports (reporter_id, target_type, target_id) WHERE status = 'open';
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cats_created_at_id_desc_deleted_at_null;
+DROP INDEX IF EXISTS idx_matches_created_at_id_desc_deleted_at_null;
+
+CREATE INDEX IF NOT EXISTS idx_cats_created_at_desc_deleted_at_null ON cats (created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_matches_created_at_desc_deleted_at_null ON matches (created_at DESC) WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_cats_created_at_desc_deleted_at_null;
+DROP INDEX IF EXISTS idx_matches_created_at_desc_deleted_at_null;
+
+-- listings page by (created_at, id), the id breaks ties between rows created in the same instant
+CREATE INDEX IF NOT EXISTS idx_cats_created_at_id_desc_deleted_at_null ON cats (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_matches_created_at_id_desc_deleted_at_null ON matches (created_at DESC, id DESC) WHERE deleted_at IS NULL;
\ No newline at end of file
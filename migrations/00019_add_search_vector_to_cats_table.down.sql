@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cats_search_vector;
+
+ALTER TABLE cats DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS cat_race_text(cat_race);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- casting an enum to text is only stable, generated columns need an immutable expression
+CREATE OR REPLACE FUNCTION cat_race_text(race cat_race) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$ SELECT race::TEXT $$;
+
+ALTER TABLE cats
+    ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', name), 'A') ||
+        setweight(to_tsvector('english', description), 'B') ||
+        setweight(to_tsvector('english', cat_race_text(race)), 'C')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_cats_search_vector ON cats USING GIN (search_vector);
\ No newline at end of file
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cats_race_text_id_deleted_at_null;
+DROP INDEX IF EXISTS idx_cats_name_id_deleted_at_null;
+DROP INDEX IF EXISTS idx_cats_age_in_month_id_deleted_at_null;
+
+CREATE INDEX IF NOT EXISTS idx_cats_age_in_month ON cats (age_in_month);
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- every sortable column gets a (column, id) index matching the order listings use, the age index
+-- also keeps serving age range filters
+DROP INDEX IF EXISTS idx_cats_age_in_month;
+
+CREATE INDEX IF NOT EXISTS idx_cats_age_in_month_id_deleted_at_null ON cats (age_in_month, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cats_name_id_deleted_at_null ON cats (name, id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cats_race_text_id_deleted_at_null ON cats (cat_race_text(race), id) WHERE deleted_at IS NULL;
\ No newline at end of file